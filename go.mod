@@ -4,6 +4,7 @@ go 1.22.10
 
 require (
 	github.com/edgexfoundry/device-sdk-go/v3 v3.1.1
+	github.com/edgexfoundry/go-mod-bootstrap/v3 v3.1.0
 	github.com/edgexfoundry/go-mod-core-contracts/v3 v3.1.0
 	github.com/go-playground/validator/v10 v10.25.0
 	github.com/gopcua/opcua v0.6.5
@@ -19,7 +20,6 @@ require (
 	github.com/cenkalti/backoff v2.2.1+incompatible // indirect
 	github.com/davecgh/go-spew v1.1.2-0.20180830191138-d8f796af33cc // indirect
 	github.com/eclipse/paho.mqtt.golang v1.4.3 // indirect
-	github.com/edgexfoundry/go-mod-bootstrap/v3 v3.1.0 // indirect
 	github.com/edgexfoundry/go-mod-configuration/v3 v3.1.0 // indirect
 	github.com/edgexfoundry/go-mod-messaging/v3 v3.1.0 // indirect
 	github.com/edgexfoundry/go-mod-registry/v3 v3.1.0 // indirect
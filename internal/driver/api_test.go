@@ -104,7 +104,7 @@ func Test_handleMethodCall(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			d, dsMock := newMockDriver(t)
 			if tt.deviceName != "" {
-				d.serverMap[tt.deviceName] = server.NewServer(tt.deviceName, dsMock)
+				d.serverMap[tt.deviceName] = server.NewServer(tt.deviceName, dsMock, nil)
 				dsMock.On("GetDeviceByName", tt.deviceName).Return(models.Device{Name: tt.deviceName}, nil)
 				dsMock.On("DeviceResource", tt.deviceName, tt.methodName).Return(tt.resource, true)
 			}
@@ -0,0 +1,129 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2022 Schneider Electric
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/edgexfoundry/device-opcua-go/internal/pki"
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/dtos/common"
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	TrustedCertificatesRoute  = "/api/v3/certificates/trusted"
+	TrustedCertificateRoute   = "/api/v3/certificates/trusted/:thumbprint"
+	RejectedCertificatesRoute = "/api/v3/certificates/rejected"
+	ApproveCertificateRoute   = "/api/v3/certificates/rejected/:thumbprint/approve"
+	thumbprintParam           = "thumbprint"
+	correlationHeader         = "X-Correlation-ID"
+)
+
+type CertificateRequest struct {
+	Certificate string `json:"certificate" validate:"required"`
+}
+
+func (r *CertificateRequest) validate() error {
+	if validate == nil {
+		validate = validator.New()
+	}
+
+	return validate.Struct(r)
+}
+
+type CertificatesResponse struct {
+	common.BaseResponse `json:",inline"`
+	Certificates        []pki.CertificateInfo `json:"certificates"`
+}
+
+type CertificateResponse struct {
+	common.BaseResponse `json:",inline"`
+	Certificate         pki.CertificateInfo `json:"certificate"`
+}
+
+func handleListTrustedCertificates(e echo.Context) error {
+	return listCertificates(e, pki.TrustedDir)
+}
+
+func handleListRejectedCertificates(e echo.Context) error {
+	return listCertificates(e, pki.RejectedDir)
+}
+
+func listCertificates(e echo.Context, folder string) error {
+	certs, err := driver.pki.ListCertificates(folder)
+	if err != nil {
+		driver.sdk.LoggingClient().Error(err.Error())
+		return echo.NewHTTPError(http.StatusInternalServerError, "error reading certificates")
+	}
+
+	response := CertificatesResponse{
+		BaseResponse: common.NewBaseResponse(e.Request().Header.Get(correlationHeader), "", http.StatusOK),
+		Certificates: certs,
+	}
+	return e.JSON(http.StatusOK, response)
+}
+
+func handleApproveCertificate(e echo.Context) error {
+	info, err := driver.pki.Approve(e.Param(thumbprintParam))
+	return certificateChanged(e, info, err, http.StatusOK)
+}
+
+func handleDeleteTrustedCertificate(e echo.Context) error {
+	info, err := driver.pki.RemoveTrusted(e.Param(thumbprintParam))
+	return certificateChanged(e, info, err, http.StatusOK)
+}
+
+func handleAddTrustedCertificate(e echo.Context) error {
+	r := e.Request()
+	if r.Body == nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "request body required")
+	}
+	defer r.Body.Close()
+
+	var req CertificateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		driver.sdk.LoggingClient().Errorf("invalid request: %v", err)
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid request")
+	}
+
+	if err := req.validate(); err != nil {
+		msg := fmt.Sprintf("invalid request: %v", err)
+		driver.sdk.LoggingClient().Error(msg)
+		return echo.NewHTTPError(http.StatusBadRequest, msg)
+	}
+
+	info, err := driver.pki.AddTrusted([]byte(req.Certificate))
+	if err != nil {
+		driver.sdk.LoggingClient().Error(err.Error())
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid certificate")
+	}
+	return certificateChanged(e, info, nil, http.StatusCreated)
+}
+
+// certificateChanged answers a trust list change and reconnects the devices that rely on the trust list
+func certificateChanged(e echo.Context, info pki.CertificateInfo, err error, status int) error {
+	if errors.Is(err, pki.ErrCertificateNotFound) {
+		return echo.NewHTTPError(http.StatusNotFound, "certificate not found")
+	}
+	if err != nil {
+		driver.sdk.LoggingClient().Error(err.Error())
+		return echo.NewHTTPError(http.StatusInternalServerError, "error updating certificates")
+	}
+
+	driver.sdk.LoggingClient().Infof("trust list updated for certificate %s", info.Thumbprint)
+	driver.reconnectSecuredDevices()
+
+	response := CertificateResponse{
+		BaseResponse: common.NewBaseResponse(e.Request().Header.Get(correlationHeader), "", status),
+		Certificate:  info,
+	}
+	return e.JSON(status, response)
+}
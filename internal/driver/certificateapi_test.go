@@ -0,0 +1,147 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2022 Schneider Electric
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/edgexfoundry/device-opcua-go/internal/pki"
+	"github.com/labstack/echo/v4"
+)
+
+func newTestCA(t *testing.T) string {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+}
+
+func newCertificateContext(method string, body io.Reader, thumbprint string) (echo.Context, *httptest.ResponseRecorder) {
+	request := httptest.NewRequest(method, "/", body)
+	recorder := httptest.NewRecorder()
+	c := echo.New().NewContext(request, recorder)
+	if thumbprint != "" {
+		c.SetParamNames(thumbprintParam)
+		c.SetParamValues(thumbprint)
+	}
+	return c, recorder
+}
+
+func Test_handleCertificates(t *testing.T) {
+	d, _ := newMockDriver(t)
+	d.pki = pki.NewStore(t.TempDir(), "urn:test")
+
+	ca := newTestCA(t)
+	body, _ := json.Marshal(CertificateRequest{Certificate: ca})
+
+	t.Run("add trusted certificate", func(t *testing.T) {
+		c, rec := newCertificateContext(http.MethodPost, bytes.NewBuffer(body), "")
+		if err := handleAddTrustedCertificate(c); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if rec.Code != http.StatusCreated {
+			t.Errorf("expected status %d, got %d", http.StatusCreated, rec.Code)
+		}
+	})
+
+	var thumbprint string
+	t.Run("list trusted certificates", func(t *testing.T) {
+		c, rec := newCertificateContext(http.MethodGet, nil, "")
+		if err := handleListTrustedCertificates(c); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		var response CertificatesResponse
+		if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+			t.Fatal(err)
+		}
+		if len(response.Certificates) != 1 || !response.Certificates[0].IsCA {
+			t.Fatalf("expected one CA certificate, got %+v", response.Certificates)
+		}
+		thumbprint = response.Certificates[0].Thumbprint
+	})
+
+	t.Run("list rejected certificates", func(t *testing.T) {
+		c, rec := newCertificateContext(http.MethodGet, nil, "")
+		if err := handleListRejectedCertificates(c); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if rec.Code != http.StatusOK {
+			t.Errorf("expected status %d, got %d", http.StatusOK, rec.Code)
+		}
+	})
+
+	t.Run("approve unknown certificate", func(t *testing.T) {
+		c, _ := newCertificateContext(http.MethodPost, nil, "unknown")
+		assertHTTPError(t, handleApproveCertificate(c), http.StatusNotFound)
+	})
+
+	t.Run("add invalid certificate", func(t *testing.T) {
+		c, _ := newCertificateContext(http.MethodPost, bytes.NewBufferString(`{"certificate":"foobar"}`), "")
+		assertHTTPError(t, handleAddTrustedCertificate(c), http.StatusBadRequest)
+	})
+
+	t.Run("add certificate bundle", func(t *testing.T) {
+		bundle, _ := json.Marshal(CertificateRequest{Certificate: newTestCA(t) + newTestCA(t)})
+		c, _ := newCertificateContext(http.MethodPost, bytes.NewBuffer(bundle), "")
+		assertHTTPError(t, handleAddTrustedCertificate(c), http.StatusBadRequest)
+	})
+
+	t.Run("add without certificate", func(t *testing.T) {
+		c, _ := newCertificateContext(http.MethodPost, bytes.NewBufferString(`{}`), "")
+		assertHTTPError(t, handleAddTrustedCertificate(c), http.StatusBadRequest)
+	})
+
+	t.Run("delete trusted certificate", func(t *testing.T) {
+		c, rec := newCertificateContext(http.MethodDelete, nil, thumbprint)
+		if err := handleDeleteTrustedCertificate(c); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if rec.Code != http.StatusOK {
+			t.Errorf("expected status %d, got %d", http.StatusOK, rec.Code)
+		}
+
+		c, _ = newCertificateContext(http.MethodDelete, nil, thumbprint)
+		assertHTTPError(t, handleDeleteTrustedCertificate(c), http.StatusNotFound)
+	})
+}
+
+func assertHTTPError(t *testing.T, err error, status int) {
+	t.Helper()
+	httpErr, ok := err.(*echo.HTTPError)
+	if !ok {
+		t.Fatalf("expected HTTP error, got = %v", err)
+	}
+	if httpErr.Code != status {
+		t.Errorf("expected status %d, got %d", status, httpErr.Code)
+	}
+}
@@ -0,0 +1,124 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2022 Schneider Electric
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/edgexfoundry/device-opcua-go/internal/server"
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/dtos/common"
+	"github.com/go-playground/validator/v10"
+	"github.com/gopcua/opcua/ua"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	AcknowledgeConditionRoute = "/api/v3/conditions/acknowledge"
+	ConfirmConditionRoute     = "/api/v3/conditions/confirm"
+)
+
+// ConditionRequest identifies the event of a condition, as published in the ConditionId
+// and EventId fields of an event reading. The EventId is base64 encoded.
+type ConditionRequest struct {
+	DeviceName  string `json:"device" validate:"required"`
+	ConditionID string `json:"conditionId" validate:"required"`
+	EventID     string `json:"eventId" validate:"required,base64"`
+	Comment     string `json:"comment,omitempty"`
+}
+
+func (r *ConditionRequest) validate() error {
+	if validate == nil {
+		validate = validator.New()
+	}
+
+	return validate.Struct(r)
+}
+
+// conditionStatuses maps the StatusCodes returned by the condition methods to HTTP statuses
+var conditionStatuses = map[ua.StatusCode]int{
+	ua.StatusBadEventIDUnknown:                  http.StatusNotFound,
+	ua.StatusBadNodeIDUnknown:                   http.StatusNotFound,
+	ua.StatusBadNodeIDInvalid:                   http.StatusBadRequest,
+	ua.StatusBadMethodInvalid:                   http.StatusBadRequest,
+	ua.StatusBadArgumentsMissing:                http.StatusBadRequest,
+	ua.StatusBadInvalidArgument:                 http.StatusBadRequest,
+	ua.StatusBadTypeMismatch:                    http.StatusBadRequest,
+	ua.StatusBadConditionBranchAlreadyAcked:     http.StatusConflict,
+	ua.StatusBadConditionBranchAlreadyConfirmed: http.StatusConflict,
+	ua.StatusBadConditionAlreadyDisabled:        http.StatusConflict,
+	ua.StatusBadUserAccessDenied:                http.StatusForbidden,
+	ua.StatusBadNotExecutable:                   http.StatusForbidden,
+	ua.StatusBadTimeout:                         http.StatusGatewayTimeout,
+}
+
+func handleAcknowledgeCondition(e echo.Context) error {
+	return handleConditionMethod(e, (*server.Server).AcknowledgeCondition)
+}
+
+func handleConfirmCondition(e echo.Context) error {
+	return handleConditionMethod(e, (*server.Server).ConfirmCondition)
+}
+
+func handleConditionMethod(e echo.Context, call func(s *server.Server, conditionID string, eventID []byte, comment string) error) error {
+	r := e.Request()
+	if r.Body == nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "request body required")
+	}
+	defer r.Body.Close()
+
+	var req ConditionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		driver.sdk.LoggingClient().Errorf("invalid request: %v", err)
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid request")
+	}
+
+	if err := req.validate(); err != nil {
+		msg := fmt.Sprintf("invalid request: %v", err)
+		driver.sdk.LoggingClient().Error(msg)
+		return echo.NewHTTPError(http.StatusBadRequest, msg)
+	}
+	eventID, err := base64.StdEncoding.DecodeString(req.EventID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("invalid request: invalid eventId: %v", err))
+	}
+
+	driver.mu.Lock()
+	s, ok := driver.serverMap[req.DeviceName]
+	driver.mu.Unlock()
+	// a removed device is left in the map without server
+	if !ok || s == nil {
+		return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("device %s not found", req.DeviceName))
+	}
+
+	if err := call(s, req.ConditionID, eventID, req.Comment); err != nil {
+		driver.sdk.LoggingClient().Error(err.Error())
+		return conditionError(err)
+	}
+
+	response := common.NewBaseResponse(r.Header.Get(correlationHeader), "", http.StatusOK)
+	return e.JSON(http.StatusOK, response)
+}
+
+// conditionError answers with the HTTP status matching the cause of a failed condition method call
+func conditionError(err error) error {
+	if errors.Is(err, server.ErrDeviceUnavailable) {
+		return echo.NewHTTPError(http.StatusLocked, server.ErrDeviceUnavailable.Error())
+	}
+	var status ua.StatusCode
+	if errors.As(err, &status) {
+		if code, ok := conditionStatuses[status]; ok {
+			return echo.NewHTTPError(code, err.Error())
+		}
+		// any other StatusCode is an error reported by the OPC UA server
+		return echo.NewHTTPError(http.StatusBadGateway, err.Error())
+	}
+	return echo.NewHTTPError(http.StatusInternalServerError, "error interacting with device")
+}
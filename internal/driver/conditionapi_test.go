@@ -0,0 +1,136 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2022 Schneider Electric
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/edgexfoundry/device-opcua-go/internal/server"
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/models"
+	"github.com/gopcua/opcua/ua"
+	"github.com/labstack/echo/v4"
+)
+
+func TestConditionRequest_validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     ConditionRequest
+		wantErr bool
+	}{
+		{
+			name:    "NOK - missing device name",
+			req:     ConditionRequest{ConditionID: "ns=2;s=Alarm", EventID: "AQI="},
+			wantErr: true,
+		},
+		{
+			name:    "NOK - missing condition id",
+			req:     ConditionRequest{DeviceName: "Device", EventID: "AQI="},
+			wantErr: true,
+		},
+		{
+			name:    "NOK - event id not base64",
+			req:     ConditionRequest{DeviceName: "Device", ConditionID: "ns=2;s=Alarm", EventID: "not base64"},
+			wantErr: true,
+		},
+		{
+			name: "OK",
+			req:  ConditionRequest{DeviceName: "Device", ConditionID: "ns=2;s=Alarm", EventID: "AQI=", Comment: "checked"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.req.validate(); (err != nil) != tt.wantErr {
+				t.Errorf("ConditionRequest.validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_handleConditionMethod(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       io.Reader
+		device     *models.Device
+		removed    bool
+		wantStatus int
+	}{
+		{
+			name:       "NOK - invalid body",
+			body:       strings.NewReader(""),
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "NOK - invalid request",
+			body:       strings.NewReader(`{"device":"test"}`),
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "NOK - device not found",
+			body:       strings.NewReader(`{"device":"test","conditionId":"ns=2;s=Alarm","eventId":"AQI="}`),
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "NOK - device removed",
+			body:       strings.NewReader(`{"device":"test","conditionId":"ns=2;s=Alarm","eventId":"AQI="}`),
+			removed:    true,
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "NOK - device locked",
+			body:       strings.NewReader(`{"device":"test","conditionId":"ns=2;s=Alarm","eventId":"AQI="}`),
+			device:     &models.Device{Name: "test", AdminState: models.Locked},
+			wantStatus: http.StatusLocked,
+		},
+		{
+			name:       "NOK - invalid condition id",
+			body:       strings.NewReader(`{"device":"test","conditionId":"ns=x;i=1","eventId":"AQI="}`),
+			device:     &models.Device{Name: "test", AdminState: models.Unlocked, OperatingState: models.Up},
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, dsMock := newMockDriver(t)
+			if tt.device != nil {
+				d.serverMap[tt.device.Name] = server.NewServer(tt.device.Name, dsMock, nil)
+				dsMock.On("GetDeviceByName", tt.device.Name).Return(*tt.device, nil)
+			}
+			if tt.removed {
+				d.serverMap["test"] = nil
+			}
+			request := httptest.NewRequest(http.MethodPost, AcknowledgeConditionRoute, tt.body)
+			c := echo.New().NewContext(request, httptest.NewRecorder())
+			assertHTTPError(t, handleAcknowledgeCondition(c), tt.wantStatus)
+		})
+	}
+}
+
+func Test_conditionError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{name: "device unavailable", err: fmt.Errorf("[test] %w", server.ErrDeviceUnavailable), want: http.StatusLocked},
+		{name: "unknown event", err: fmt.Errorf("[test] failed: %w", ua.StatusBadEventIDUnknown), want: http.StatusNotFound},
+		{name: "already acknowledged", err: fmt.Errorf("[test] failed: %w", ua.StatusBadConditionBranchAlreadyAcked), want: http.StatusConflict},
+		{name: "access denied", err: fmt.Errorf("[test] failed: %w", ua.StatusBadUserAccessDenied), want: http.StatusForbidden},
+		{name: "other status", err: fmt.Errorf("[test] failed: %w", ua.StatusBadInternalError), want: http.StatusBadGateway},
+		{name: "other error", err: errors.New("failed"), want: http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertHTTPError(t, conditionError(tt.err), tt.want)
+		})
+	}
+}
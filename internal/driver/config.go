@@ -0,0 +1,95 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2022 Schneider Electric
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/edgexfoundry/device-opcua-go/internal/server"
+)
+
+const (
+	PKIDir                   string = "PKIDir"
+	ApplicationURI           string = "ApplicationURI"
+	ReconnectInitialInterval string = "ReconnectInitialInterval"
+	ReconnectMaxInterval     string = "ReconnectMaxInterval"
+	ReconnectMultiplier      string = "ReconnectMultiplier"
+	DownAfterFailedAttempts  string = "DownAfterFailedAttempts"
+
+	defaultPKIDir string = "./res/pki"
+)
+
+// ServiceConfig holds the service wide settings read from the Driver configuration section
+type ServiceConfig struct {
+	PKIDir         string
+	ApplicationURI string
+	Reconnect      server.ReconnectPolicy
+}
+
+// NewServiceConfig reads the service wide settings from the Driver configuration
+// section, falling back to defaults for the missing ones
+func NewServiceConfig(driverConfigs map[string]string) (*ServiceConfig, error) {
+	cfg := &ServiceConfig{
+		PKIDir:         driverConfigs[PKIDir],
+		ApplicationURI: driverConfigs[ApplicationURI],
+		Reconnect:      server.DefaultReconnectPolicy(),
+	}
+
+	if cfg.PKIDir == "" {
+		cfg.PKIDir = defaultPKIDir
+	}
+	if cfg.ApplicationURI == "" {
+		hostname, err := os.Hostname()
+		if err != nil {
+			hostname = "localhost"
+		}
+		cfg.ApplicationURI = fmt.Sprintf("urn:%s:edgexfoundry:device-opcua", hostname)
+	}
+
+	if err := parseDuration(driverConfigs, ReconnectInitialInterval, &cfg.Reconnect.InitialInterval); err != nil {
+		return nil, err
+	}
+	if err := parseDuration(driverConfigs, ReconnectMaxInterval, &cfg.Reconnect.MaxInterval); err != nil {
+		return nil, err
+	}
+	if v, ok := driverConfigs[ReconnectMultiplier]; ok && v != "" {
+		multiplier, err := strconv.ParseFloat(v, 64)
+		if err != nil || multiplier < 1 {
+			return nil, fmt.Errorf("invalid %s %q: must be a number greater than or equal to 1", ReconnectMultiplier, v)
+		}
+		cfg.Reconnect.Multiplier = multiplier
+	}
+	if v, ok := driverConfigs[DownAfterFailedAttempts]; ok && v != "" {
+		attempts, err := strconv.Atoi(v)
+		if err != nil || attempts < 1 {
+			return nil, fmt.Errorf("invalid %s %q: must be a positive integer", DownAfterFailedAttempts, v)
+		}
+		cfg.Reconnect.DownAfterFailures = attempts
+	}
+	if cfg.Reconnect.MaxInterval < cfg.Reconnect.InitialInterval {
+		return nil, fmt.Errorf("%s must not be lower than %s", ReconnectMaxInterval, ReconnectInitialInterval)
+	}
+
+	return cfg, nil
+}
+
+// parseDuration overrides d with the positive duration set for key, if any
+func parseDuration(driverConfigs map[string]string, key string, d *time.Duration) error {
+	v, ok := driverConfigs[key]
+	if !ok || v == "" {
+		return nil
+	}
+	parsed, err := time.ParseDuration(v)
+	if err != nil || parsed <= 0 {
+		return fmt.Errorf("invalid %s %q: must be a positive duration", key, v)
+	}
+	*d = parsed
+	return nil
+}
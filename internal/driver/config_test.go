@@ -0,0 +1,95 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2022 Schneider Electric
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/edgexfoundry/device-opcua-go/internal/server"
+)
+
+func TestNewServiceConfig(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		cfg, err := NewServiceConfig(nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if cfg.PKIDir != defaultPKIDir {
+			t.Errorf("expected PKIDir %s, got %s", defaultPKIDir, cfg.PKIDir)
+		}
+		if !strings.HasPrefix(cfg.ApplicationURI, "urn:") {
+			t.Errorf("expected a default application URI, got %s", cfg.ApplicationURI)
+		}
+		if cfg.Reconnect != server.DefaultReconnectPolicy() {
+			t.Errorf("expected the default reconnect policy, got %+v", cfg.Reconnect)
+		}
+	})
+
+	t.Run("configured", func(t *testing.T) {
+		cfg, err := NewServiceConfig(map[string]string{PKIDir: "/pki", ApplicationURI: "urn:test"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if cfg.PKIDir != "/pki" || cfg.ApplicationURI != "urn:test" {
+			t.Errorf("unexpected config %+v", cfg)
+		}
+	})
+}
+
+func TestNewServiceConfig_Reconnect(t *testing.T) {
+	tests := []struct {
+		name    string
+		configs map[string]string
+		want    server.ReconnectPolicy
+		wantErr bool
+	}{
+		{
+			name:    "OK - configured backoff",
+			configs: map[string]string{ReconnectInitialInterval: "500ms", ReconnectMaxInterval: "30s", ReconnectMultiplier: "1.5", DownAfterFailedAttempts: "5"},
+			want:    server.ReconnectPolicy{InitialInterval: 500 * time.Millisecond, MaxInterval: 30 * time.Second, Multiplier: 1.5, DownAfterFailures: 5},
+		},
+		{
+			name:    "NOK - invalid duration",
+			configs: map[string]string{ReconnectInitialInterval: "soon"},
+			wantErr: true,
+		},
+		{
+			name:    "NOK - negative duration",
+			configs: map[string]string{ReconnectMaxInterval: "-1s"},
+			wantErr: true,
+		},
+		{
+			name:    "NOK - multiplier lower than 1",
+			configs: map[string]string{ReconnectMultiplier: "0.5"},
+			wantErr: true,
+		},
+		{
+			name:    "NOK - no failed attempt before down",
+			configs: map[string]string{DownAfterFailedAttempts: "0"},
+			wantErr: true,
+		},
+		{
+			name:    "NOK - max interval lower than initial interval",
+			configs: map[string]string{ReconnectInitialInterval: "10s", ReconnectMaxInterval: "1s"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := NewServiceConfig(tt.configs)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewServiceConfig() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && cfg.Reconnect != tt.want {
+				t.Errorf("NewServiceConfig() reconnect = %+v, want %+v", cfg.Reconnect, tt.want)
+			}
+		})
+	}
+}
@@ -14,10 +14,13 @@ import (
 	"sync"
 	"time"
 
+	"github.com/edgexfoundry/device-opcua-go/internal/pki"
 	"github.com/edgexfoundry/device-opcua-go/internal/server"
 	"github.com/edgexfoundry/device-sdk-go/v3/pkg/interfaces"
 	sdkModel "github.com/edgexfoundry/device-sdk-go/v3/pkg/models"
+	"github.com/edgexfoundry/go-mod-bootstrap/v3/bootstrap/secret"
 	"github.com/edgexfoundry/go-mod-core-contracts/v3/models"
+	"github.com/labstack/echo/v4"
 )
 
 var once sync.Once
@@ -28,6 +31,10 @@ type Driver struct {
 	mu        sync.Mutex
 	serverMap map[string]*server.Server
 	sdk       interfaces.DeviceServiceSDK
+	pki       *pki.Store
+	reconnect server.ReconnectPolicy
+	// restart reopens the connection of a device, replaced in tests
+	restart func(s *server.Server)
 }
 
 // NewProtocolDriver returns a new protocol driver object
@@ -42,10 +49,26 @@ func NewProtocolDriver() interfaces.ProtocolDriver {
 func (d *Driver) Initialize(sdk interfaces.DeviceServiceSDK) error {
 	d.sdk = sdk
 
+	// The PKI store holds the application instance certificate shared by all devices
+	cfg, err := NewServiceConfig(d.sdk.DriverConfigs())
+	if err != nil {
+		return fmt.Errorf("invalid driver configuration: %v", err)
+	}
+	d.pki = pki.NewStore(cfg.PKIDir, cfg.ApplicationURI)
+	d.reconnect = cfg.Reconnect
+
 	// Define custom API endpoints
 	if err := d.sdk.AddCustomRoute("/api/v3/call", interfaces.Authenticated, handleMethodCall, http.MethodPost); err != nil {
 		return fmt.Errorf("unable to add custom route to device service: %v", err)
 	}
+	if err := d.addCustomRoutes(); err != nil {
+		return fmt.Errorf("unable to add custom route to device service: %v", err)
+	}
+
+	// Reconnect the devices using a secret whenever it changes
+	if err := d.sdk.SecretProvider().RegisterSecretUpdatedCallback(secret.WildcardName, d.onSecretUpdated); err != nil {
+		d.sdk.LoggingClient().Warnf("unable to register secret updated callback: %v", err)
+	}
 
 	d.mu.Lock()
 	d.serverMap = make(map[string]*server.Server)
@@ -66,11 +89,11 @@ func (d *Driver) Initialize(sdk interfaces.DeviceServiceSDK) error {
 func (d *Driver) AddDevice(deviceName string, protocols map[string]models.ProtocolProperties, adminState models.AdminState) error {
 	d.sdk.LoggingClient().Debugf("Device %s is added. Starting subscription mechanism...", deviceName)
 	d.mu.Lock()
-	s := server.NewServer(deviceName, d.sdk)
+	s := server.NewServer(deviceName, d.sdk, d.pki)
 	d.serverMap[deviceName] = s
 	d.mu.Unlock()
 
-	go s.StartSubscriptionListener() // nolint:errcheck
+	go s.RunSubscriptionListener(d.reconnect)
 	return nil
 }
 
@@ -79,8 +102,25 @@ func (d *Driver) AddDevice(deviceName string, protocols map[string]models.Protoc
 func (d *Driver) UpdateDevice(deviceName string, protocols map[string]models.ProtocolProperties, adminState models.AdminState) error {
 	d.sdk.LoggingClient().Debugf("Device %s is updated. Restarting subscription mechanism...", deviceName)
 	if s, ok := d.serverMap[deviceName]; ok {
+		// The device is also updated when the driver reports its operating state.
+		// When the protocols are unchanged, a new admin state pauses or resumes
+		// the monitored items; the listener is restarted if there are none yet.
+		// A change limited to the Resources updates the monitored items in place.
+		if !s.ConnectionChanged(protocols) {
+			var err error
+			if s.ProtocolsChanged(protocols) {
+				err = s.UpdateResources(protocols)
+			}
+			if err == nil {
+				err = s.SetAdminState(adminState)
+			}
+			if err == nil {
+				return nil
+			}
+			d.sdk.LoggingClient().Debugf("[%s] %v. Restarting subscription mechanism...", deviceName, err)
+		}
 		s.Cleanup(true)
-		go s.StartSubscriptionListener() // nolint:errcheck
+		go s.RunSubscriptionListener(d.reconnect)
 		return nil
 	}
 
@@ -109,7 +149,22 @@ func (d *Driver) ValidateDevice(device models.Device) error {
 		return fmt.Errorf("error reading protocol properties, %v", err)
 	}
 
-	return server.Validate(cfg)
+	if err := server.Validate(cfg); err != nil {
+		return err
+	}
+
+	// The profile may not be cached yet when the device is provisioned along with it
+	profile, err := d.sdk.GetProfileByName(device.ProfileName)
+	if err != nil {
+		d.sdk.LoggingClient().Warnf("[%s] device resources not validated until subscribed: %v", device.Name, err)
+		return nil
+	}
+	for _, resource := range profile.DeviceResources {
+		if err := server.ValidateResource(resource); err != nil {
+			return fmt.Errorf("invalid device profile %s: %v", profile.Name, err)
+		}
+	}
+	return nil
 }
 
 func (d *Driver) Discover() error {
@@ -167,6 +222,61 @@ func (d *Driver) HandleWriteCommands(deviceName string, protocols map[string]mod
 	return s.ProcessWriteCommands(reqs, params)
 }
 
+// addCustomRoutes defines the custom API endpoints managing the certificate trust list and the conditions
+func (d *Driver) addCustomRoutes() error {
+	routes := []struct {
+		route   string
+		handler func(e echo.Context) error
+		method  string
+	}{
+		{TrustedCertificatesRoute, handleListTrustedCertificates, http.MethodGet},
+		{TrustedCertificatesRoute, handleAddTrustedCertificate, http.MethodPost},
+		{TrustedCertificateRoute, handleDeleteTrustedCertificate, http.MethodDelete},
+		{RejectedCertificatesRoute, handleListRejectedCertificates, http.MethodGet},
+		{ApproveCertificateRoute, handleApproveCertificate, http.MethodPost},
+		{AcknowledgeConditionRoute, handleAcknowledgeCondition, http.MethodPost},
+		{ConfirmConditionRoute, handleConfirmCondition, http.MethodPost},
+	}
+
+	for _, r := range routes {
+		if err := d.sdk.AddCustomRoute(r.route, interfaces.Authenticated, r.handler, r.method); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// reconnectSecuredDevices restarts the devices whose connection depends on the trust list
+func (d *Driver) reconnectSecuredDevices() {
+	d.restartDevices("trust list changed", (*server.Server).UsesTrustList)
+}
+
+// onSecretUpdated restarts the devices using a secret, so that they connect with the new credentials
+func (d *Driver) onSecretUpdated(secretName string) {
+	d.restartDevices(fmt.Sprintf("secret %s updated", secretName), func(s *server.Server) bool {
+		return s.UsesSecret(secretName)
+	})
+}
+
+// restartDevices closes and reopens the connection of the devices matching the filter
+func (d *Driver) restartDevices(reason string, filter func(s *server.Server) bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for name, s := range d.serverMap {
+		if s == nil || !filter(s) {
+			continue
+		}
+		d.sdk.LoggingClient().Infof("[%s] %s. Restarting subscription mechanism...", name, reason)
+		if d.restart != nil {
+			d.restart(s)
+			continue
+		}
+		s.Cleanup(true)
+		go s.RunSubscriptionListener(d.reconnect)
+	}
+}
+
 func serverNotFoundError(deviceName string) error {
 	return fmt.Errorf("unable to find device %s in server map", deviceName)
 }
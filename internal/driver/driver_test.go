@@ -10,12 +10,15 @@ import (
 	"fmt"
 	"net/http"
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/edgexfoundry/device-opcua-go/internal/server"
 	"github.com/edgexfoundry/device-opcua-go/internal/test"
 	"github.com/edgexfoundry/device-sdk-go/v3/pkg/interfaces/mocks"
 	sdkModel "github.com/edgexfoundry/device-sdk-go/v3/pkg/models"
+	bootstrapMocks "github.com/edgexfoundry/go-mod-bootstrap/v3/bootstrap/interfaces/mocks"
+	"github.com/edgexfoundry/go-mod-bootstrap/v3/bootstrap/secret"
 	"github.com/edgexfoundry/go-mod-core-contracts/v3/models"
 	"github.com/stretchr/testify/mock"
 )
@@ -196,8 +199,15 @@ func TestDriver_Initialize(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			d, dsMock := newMockDriver(t)
+			dsMock.On("DriverConfigs").Return(map[string]string{PKIDir: t.TempDir()})
 			dsMock.On("AddCustomRoute", "/api/v3/call", mock.Anything, mock.AnythingOfType("func(echo.Context) error"), http.MethodPost).Return(tt.err)
 			if tt.err == nil {
+				dsMock.On("AddCustomRoute", mock.MatchedBy(func(route string) bool {
+					return strings.HasPrefix(route, "/api/v3/certificates/") || strings.HasPrefix(route, "/api/v3/conditions/")
+				}), mock.Anything, mock.AnythingOfType("func(echo.Context) error"), mock.Anything).Return(nil)
+				spMock := bootstrapMocks.NewSecretProvider(t)
+				spMock.On("RegisterSecretUpdatedCallback", secret.WildcardName, mock.Anything).Return(nil)
+				dsMock.On("SecretProvider").Return(spMock)
 				dsMock.On("Devices").Return(tt.devices)
 			}
 			if err := d.Initialize(dsMock); (err != nil) != tt.wantErr {
@@ -207,10 +217,19 @@ func TestDriver_Initialize(t *testing.T) {
 	}
 }
 
+func TestDriver_Initialize_invalidConfig(t *testing.T) {
+	d, dsMock := newMockDriver(t)
+	dsMock.On("DriverConfigs").Return(map[string]string{ReconnectMultiplier: "0"})
+	if err := d.Initialize(dsMock); err == nil {
+		t.Error("expected invalid driver configuration error")
+	}
+}
+
 func TestDriver_ValidateDevice(t *testing.T) {
 	tests := []struct {
 		name    string
 		device  models.Device
+		profile *models.DeviceProfile
 		wantErr bool
 	}{
 		{
@@ -230,14 +249,71 @@ func TestDriver_ValidateDevice(t *testing.T) {
 				"CertFile":  "",
 				"KeyFile":   "",
 			}}},
+			profile: &models.DeviceProfile{DeviceResources: []models.DeviceResource{
+				{Name: "A", Attributes: map[string]any{"nodeId": "ns=2;s=A", "samplingInterval": 100, "queueSize": "5", "discardOldest": false}},
+			}},
+		},
+		{
+			name: "OK - profile not cached",
+			device: models.Device{Protocols: map[string]models.ProtocolProperties{"opcua": {
+				"Endpoint": "opc.tcp://test",
+				"Policy":   "None",
+				"Mode":     "None",
+			}}},
+		},
+		{
+			name: "NOK - invalid monitoring attributes",
+			device: models.Device{Protocols: map[string]models.ProtocolProperties{"opcua": {
+				"Endpoint": "opc.tcp://test",
+				"Policy":   "None",
+				"Mode":     "None",
+			}}},
+			profile: &models.DeviceProfile{DeviceResources: []models.DeviceResource{
+				{Name: "A", Attributes: map[string]any{"nodeId": "ns=2;s=A", "queueSize": -1}},
+			}},
+			wantErr: true,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			d, _ := newMockDriver(t)
+			d, dsMock := newMockDriver(t)
+			if tt.profile != nil {
+				dsMock.On("GetProfileByName", tt.device.ProfileName).Return(*tt.profile, nil)
+			} else if !tt.wantErr {
+				dsMock.On("GetProfileByName", tt.device.ProfileName).Return(models.DeviceProfile{}, fmt.Errorf("not found"))
+			}
 			if err := d.ValidateDevice(tt.device); (err != nil) != tt.wantErr {
 				t.Errorf("Driver.ValidateDevice() error = %v, wantErr %v", err, tt.wantErr)
 			}
 		})
 	}
 }
+
+func TestDriver_onSecretUpdated(t *testing.T) {
+	t.Run("OK - devices not using the secret are left running", func(t *testing.T) {
+		d, dsMock := newMockDriver(t)
+		var restarted []*server.Server
+		d.restart = func(s *server.Server) { restarted = append(restarted, s) }
+		t.Cleanup(func() { d.restart = nil })
+
+		// an invalid endpoint fails before dialing, leaving the servers configured
+		for name, secretName := range map[string]string{"UsingSecret": "opcua", "Other": "other"} {
+			dsMock.On("GetDeviceByName", name).Return(models.Device{Name: name, Protocols: map[string]models.ProtocolProperties{
+				"opcua": {"Endpoint": "invalid://test", "Policy": "None", "Mode": "None", "SecretName": secretName},
+			}}, nil).Once()
+
+			s := server.NewServer(name, dsMock, nil)
+			if err := s.Connect(); err == nil {
+				t.Fatalf("expected the connection to %s to fail", name)
+			}
+			d.serverMap[name] = s
+		}
+		d.serverMap["Removed"] = nil
+
+		d.onSecretUpdated("opcua")
+
+		if len(restarted) != 1 || restarted[0] != d.serverMap["UsingSecret"] {
+			t.Errorf("restarted %v, want only the device using the secret", restarted)
+		}
+	})
+}
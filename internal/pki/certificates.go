@@ -0,0 +1,144 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2022 Schneider Electric
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package pki
+
+import (
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// ErrCertificateNotFound is returned when no certificate of a folder matches a thumbprint
+var ErrCertificateNotFound = errors.New("certificate not found")
+
+// CertificateInfo describes a certificate held by the store
+type CertificateInfo struct {
+	Thumbprint      string    `json:"thumbprint"`
+	Subject         string    `json:"subject"`
+	Issuer          string    `json:"issuer"`
+	NotBefore       time.Time `json:"notBefore"`
+	NotAfter        time.Time `json:"notAfter"`
+	IsCA            bool      `json:"isCA"`
+	ApplicationURIs []string  `json:"applicationURIs,omitempty"`
+	DNSNames        []string  `json:"dnsNames,omitempty"`
+}
+
+func newCertificateInfo(c *x509.Certificate) CertificateInfo {
+	info := CertificateInfo{
+		Thumbprint: Thumbprint(c.Raw),
+		Subject:    c.Subject.String(),
+		Issuer:     c.Issuer.String(),
+		NotBefore:  c.NotBefore,
+		NotAfter:   c.NotAfter,
+		IsCA:       c.IsCA,
+		DNSNames:   c.DNSNames,
+	}
+	for _, uri := range c.URIs {
+		info.ApplicationURIs = append(info.ApplicationURIs, uri.String())
+	}
+	return info
+}
+
+// ListCertificates describes the certificates of a store folder
+func (s *Store) ListCertificates(folder string) ([]CertificateInfo, error) {
+	certs, err := s.readCertificates(folder)
+	if err != nil {
+		return nil, err
+	}
+
+	infos := make([]CertificateInfo, 0, len(certs))
+	for _, c := range certs {
+		infos = append(infos, newCertificateInfo(c))
+	}
+	return infos, nil
+}
+
+// Approve moves a rejected certificate to the trust list
+func (s *Store) Approve(thumbprint string) (CertificateInfo, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	path, cert, err := s.findCertificate(RejectedDir, thumbprint)
+	if err != nil {
+		return CertificateInfo{}, err
+	}
+	if err := writeFile(filepath.Join(s.dir, TrustedDir, Thumbprint(cert.Raw)+".der"), cert.Raw); err != nil {
+		return CertificateInfo{}, err
+	}
+	if err := os.Remove(path); err != nil {
+		return CertificateInfo{}, fmt.Errorf("failed to remove %s: %v", path, err)
+	}
+	return newCertificateInfo(cert), nil
+}
+
+// RemoveTrusted deletes a certificate from the trust list
+func (s *Store) RemoveTrusted(thumbprint string) (CertificateInfo, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	path, cert, err := s.findCertificate(TrustedDir, thumbprint)
+	if err != nil {
+		return CertificateInfo{}, err
+	}
+	if err := os.Remove(path); err != nil {
+		return CertificateInfo{}, fmt.Errorf("failed to remove %s: %v", path, err)
+	}
+	return newCertificateInfo(cert), nil
+}
+
+// AddTrusted adds a PEM or DER encoded certificate, such as a CA, to the trust list.
+// Bundles are rejected, each certificate has to be added on its own.
+func (s *Store) AddTrusted(b []byte) (CertificateInfo, error) {
+	certs, err := parseCertificates(b)
+	if err != nil {
+		return CertificateInfo{}, fmt.Errorf("invalid certificate: %v", err)
+	}
+	if len(certs) > 1 {
+		return CertificateInfo{}, fmt.Errorf("invalid certificate: expected a single certificate, got %d", len(certs))
+	}
+	cert := certs[0]
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := writeFile(filepath.Join(s.dir, TrustedDir, Thumbprint(cert.Raw)+".der"), cert.Raw); err != nil {
+		return CertificateInfo{}, err
+	}
+	return newCertificateInfo(cert), nil
+}
+
+// findCertificate returns the file of a store folder holding the certificate with the given thumbprint
+func (s *Store) findCertificate(folder string, thumbprint string) (string, *x509.Certificate, error) {
+	dir := filepath.Join(s.dir, folder)
+	entries, err := os.ReadDir(dir)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return "", nil, fmt.Errorf("failed to read %s: %v", dir, err)
+	}
+
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		path := filepath.Join(dir, e.Name())
+		b, err := os.ReadFile(path) // #nosec G304 -- path is built from the configured PKI directory
+		if err != nil {
+			continue
+		}
+		certs, err := parseCertificates(b)
+		if err != nil {
+			continue
+		}
+		if strings.EqualFold(Thumbprint(certs[0].Raw), thumbprint) {
+			return path, certs[0], nil
+		}
+	}
+	return "", nil, fmt.Errorf("%s in %s: %w", thumbprint, folder, ErrCertificateNotFound)
+}
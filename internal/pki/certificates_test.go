@@ -0,0 +1,83 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2022 Schneider Electric
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package pki
+
+import (
+	"encoding/pem"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestStore_ManageCertificates(t *testing.T) {
+	valid := time.Now().Add(time.Hour)
+	server := newTestCert(t, false, nil, valid)
+	ca := newTestCert(t, true, nil, valid)
+	store := NewStore(t.TempDir(), testURI)
+
+	// an unknown server certificate lands in the rejected folder
+	if err := store.VerifyServerCertificate(server.der, testEndpoint, ""); !errors.Is(err, ErrCertificateUntrusted) {
+		t.Fatalf("expected untrusted certificate, got = %v", err)
+	}
+	assertCertificates(t, store, RejectedDir, 1)
+	assertCertificates(t, store, TrustedDir, 0)
+
+	if _, err := store.Approve("unknown"); !errors.Is(err, ErrCertificateNotFound) {
+		t.Errorf("expected certificate not found, got = %v", err)
+	}
+
+	info, err := store.Approve(Thumbprint(server.der))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if info.Thumbprint != Thumbprint(server.der) {
+		t.Errorf("unexpected thumbprint %s", info.Thumbprint)
+	}
+	assertCertificates(t, store, RejectedDir, 0)
+	assertCertificates(t, store, TrustedDir, 1)
+	if err := store.VerifyServerCertificate(server.der, testEndpoint, ""); err != nil {
+		t.Errorf("expected approved certificate to be trusted, got = %v", err)
+	}
+
+	info, err = store.AddTrusted(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ca.der}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !info.IsCA {
+		t.Error("expected uploaded certificate to be a CA")
+	}
+	assertCertificates(t, store, TrustedDir, 2)
+
+	if _, err := store.AddTrusted([]byte("foobar")); err == nil {
+		t.Error("expected error for invalid certificate")
+	}
+	bundle := append(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ca.der}),
+		pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.der})...)
+	if _, err := store.AddTrusted(bundle); err == nil {
+		t.Error("expected error for certificate bundle")
+	}
+	assertCertificates(t, store, TrustedDir, 2)
+
+	if _, err := store.RemoveTrusted(Thumbprint(server.der)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertCertificates(t, store, TrustedDir, 1)
+	if _, err := store.RemoveTrusted(Thumbprint(server.der)); !errors.Is(err, ErrCertificateNotFound) {
+		t.Errorf("expected certificate not found, got = %v", err)
+	}
+}
+
+func assertCertificates(t *testing.T, store *Store, folder string, want int) {
+	t.Helper()
+	infos, err := store.ListCertificates(folder)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(infos) != want {
+		t.Errorf("expected %d certificates in %s, got %d", want, folder, len(infos))
+	}
+}
@@ -0,0 +1,177 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2022 Schneider Electric
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package pki
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+const (
+	ownCertsDir   = "own/certs"
+	ownPrivateDir = "own/private"
+	ownCertFile   = "cert.der"
+	ownKeyFile    = "key.pem"
+
+	keySize  = 2048
+	validity = 5 * 365 * 24 * time.Hour
+)
+
+// Store is a file based PKI store following the usual OPC UA directory layout.
+// It is shared by all devices of the service.
+type Store struct {
+	dir            string
+	applicationURI string
+	mu             sync.Mutex
+	cert           []byte
+	key            *rsa.PrivateKey
+}
+
+// NewStore returns a PKI store rooted at dir. Directories are created lazily,
+// so that a read-only store is usable as long as nothing needs to be written.
+func NewStore(dir string, applicationURI string) *Store {
+	return &Store{
+		dir:            dir,
+		applicationURI: applicationURI,
+	}
+}
+
+// Dir returns the root directory of the store
+func (s *Store) Dir() string {
+	return s.dir
+}
+
+// ApplicationURI returns the application URI the instance certificate is issued for
+func (s *Store) ApplicationURI() string {
+	return s.applicationURI
+}
+
+// ApplicationCertificate returns the DER encoded application instance certificate
+// and its private key. They are loaded from the store, or generated and persisted
+// when the store does not hold any yet.
+func (s *Store) ApplicationCertificate() ([]byte, *rsa.PrivateKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.cert != nil && s.key != nil {
+		return s.cert, s.key, nil
+	}
+
+	certPath := filepath.Join(s.dir, ownCertsDir, ownCertFile)
+	keyPath := filepath.Join(s.dir, ownPrivateDir, ownKeyFile)
+
+	cert, key, err := loadCertificate(certPath, keyPath)
+	if errors.Is(err, os.ErrNotExist) {
+		cert, key, err = s.generateCertificate(certPath, keyPath)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	s.cert, s.key = cert, key
+	return cert, key, nil
+}
+
+func loadCertificate(certPath string, keyPath string) ([]byte, *rsa.PrivateKey, error) {
+	cert, err := os.ReadFile(certPath) // #nosec G304 -- path is built from the configured PKI directory
+	if err != nil {
+		return nil, nil, err
+	}
+	if _, err := x509.ParseCertificate(cert); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse application certificate %s: %v", certPath, err)
+	}
+
+	b, err := os.ReadFile(keyPath) // #nosec G304 -- path is built from the configured PKI directory
+	if err != nil {
+		return nil, nil, err
+	}
+	block, _ := pem.Decode(b)
+	if block == nil {
+		return nil, nil, fmt.Errorf("failed to decode PEM block in %s", keyPath)
+	}
+	key, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse private key %s: %v", keyPath, err)
+	}
+
+	return cert, key, nil
+}
+
+func (s *Store) generateCertificate(certPath string, keyPath string) ([]byte, *rsa.PrivateKey, error) {
+	uri, err := url.Parse(s.applicationURI)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid application URI %s: %v", s.applicationURI, err)
+	}
+
+	key, err := rsa.GenerateKey(rand.Reader, keySize)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to generate private key: %v", err)
+	}
+
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to generate serial number: %v", err)
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "localhost"
+	}
+
+	notBefore := time.Now().Add(-time.Hour)
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject: pkix.Name{
+			CommonName:   "device-opcua",
+			Organization: []string{"EdgeX Foundry"},
+		},
+		NotBefore: notBefore,
+		NotAfter:  notBefore.Add(validity),
+		KeyUsage: x509.KeyUsageDigitalSignature | x509.KeyUsageContentCommitment |
+			x509.KeyUsageKeyEncipherment | x509.KeyUsageDataEncipherment | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		URIs:                  []*url.URL{uri},
+		DNSNames:              []string{hostname},
+	}
+
+	cert, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create application certificate: %v", err)
+	}
+
+	if err := writeFile(certPath, cert); err != nil {
+		return nil, nil, err
+	}
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	if err := writeFile(keyPath, keyPEM); err != nil {
+		return nil, nil, err
+	}
+
+	return cert, key, nil
+}
+
+func writeFile(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create directory for %s: %v", path, err)
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write %s: %v", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2022 Schneider Electric
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package pki
+
+import (
+	"crypto/x509"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const testURI = "urn:test:edgexfoundry:device-opcua"
+
+func TestStore_ApplicationCertificate(t *testing.T) {
+	t.Run("generate and reuse", func(t *testing.T) {
+		dir := t.TempDir()
+
+		cert, key, err := NewStore(dir, testURI).ApplicationCertificate()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		x509Cert, err := x509.ParseCertificate(cert)
+		if err != nil {
+			t.Fatalf("unable to parse certificate: %v", err)
+		}
+		if len(x509Cert.URIs) != 1 || x509Cert.URIs[0].String() != testURI {
+			t.Errorf("expected application URI %s, got %v", testURI, x509Cert.URIs)
+		}
+		if !key.PublicKey.Equal(x509Cert.PublicKey) {
+			t.Error("private key does not match certificate")
+		}
+
+		for _, f := range []string{filepath.Join(dir, ownCertsDir, ownCertFile), filepath.Join(dir, ownPrivateDir, ownKeyFile)} {
+			if _, err := os.Stat(f); err != nil {
+				t.Errorf("expected %s to be persisted: %v", f, err)
+			}
+		}
+
+		// a new store on the same directory, as after a restart, reuses the certificate
+		reloaded, _, err := NewStore(dir, testURI).ApplicationCertificate()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if string(reloaded) != string(cert) {
+			t.Error("expected certificate to be reused")
+		}
+	})
+
+	t.Run("invalid application URI", func(t *testing.T) {
+		if _, _, err := NewStore(t.TempDir(), "%zz").ApplicationCertificate(); err == nil {
+			t.Error("expected error for invalid application URI")
+		}
+	})
+
+	t.Run("corrupt certificate", func(t *testing.T) {
+		dir := t.TempDir()
+		if err := writeFile(filepath.Join(dir, ownCertsDir, ownCertFile), []byte("foobar")); err != nil {
+			t.Fatal(err)
+		}
+		if _, _, err := NewStore(dir, testURI).ApplicationCertificate(); err == nil {
+			t.Error("expected error for corrupt certificate")
+		}
+	})
+}
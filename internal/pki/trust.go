@@ -0,0 +1,213 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2022 Schneider Electric
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package pki
+
+import (
+	"bytes"
+	"crypto/sha1" // #nosec G505 -- OPC UA identifies certificates by their SHA-1 thumbprint
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	TrustedDir  = "trusted/certs"
+	IssuersDir  = "issuers/certs"
+	RejectedDir = "rejected/certs"
+)
+
+// ErrCertificateUntrusted is returned when a certificate does not chain up to the trust list
+var ErrCertificateUntrusted = errors.New("certificate is not trusted")
+
+// Thumbprint returns the hex encoded SHA-1 thumbprint identifying a DER encoded certificate
+func Thumbprint(der []byte) string {
+	sum := sha1.Sum(der) // #nosec G401 -- OPC UA identifies certificates by their SHA-1 thumbprint
+	return hex.EncodeToString(sum[:])
+}
+
+// VerifyServerCertificate checks a server certificate, optionally followed by its chain,
+// against the trust list. The validity dates, the host of the endpoint URL and the
+// application URI the server announces are checked as well. Certificates that are not
+// trusted are copied to the rejected folder, so that an operator can promote them.
+func (s *Store) VerifyServerCertificate(chain []byte, endpointURL string, applicationURI string) error {
+	certs, err := parseCertificates(chain)
+	if err != nil {
+		return fmt.Errorf("invalid server certificate: %v", err)
+	}
+	leaf := certs[0]
+
+	now := time.Now()
+	if now.Before(leaf.NotBefore) || now.After(leaf.NotAfter) {
+		err := fmt.Errorf("server certificate %s is not valid between %s and %s", Thumbprint(leaf.Raw), leaf.NotBefore, leaf.NotAfter)
+		// an out of date certificate is recorded as well when it is not trusted either
+		if errors.Is(s.verifyChain(certs, leaf.NotBefore), ErrCertificateUntrusted) {
+			return s.rejected(leaf, err)
+		}
+		return err
+	}
+
+	if err := s.verifyChain(certs, now); err != nil {
+		if errors.Is(err, ErrCertificateUntrusted) {
+			return s.rejected(leaf, err)
+		}
+		return err
+	}
+
+	if err := verifyEndpoint(leaf, endpointURL, applicationURI); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *Store) verifyChain(certs []*x509.Certificate, now time.Time) error {
+	leaf := certs[0]
+	trusted, err := s.readCertificates(TrustedDir)
+	if err != nil {
+		return err
+	}
+
+	roots := x509.NewCertPool()
+	for _, c := range trusted {
+		// a certificate explicitly placed in the trust list is trusted as is
+		if bytes.Equal(c.Raw, leaf.Raw) {
+			return nil
+		}
+		roots.AddCert(c)
+	}
+
+	issuers, err := s.readCertificates(IssuersDir)
+	if err != nil {
+		return err
+	}
+	intermediates := x509.NewCertPool()
+	for _, c := range append(issuers, certs[1:]...) {
+		intermediates.AddCert(c)
+	}
+
+	_, err = leaf.Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		CurrentTime:   now,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	})
+	if err != nil {
+		var unknownAuthority x509.UnknownAuthorityError
+		if errors.As(err, &unknownAuthority) {
+			return fmt.Errorf("server certificate %s: %w", Thumbprint(leaf.Raw), ErrCertificateUntrusted)
+		}
+		return fmt.Errorf("server certificate %s: %v", Thumbprint(leaf.Raw), err)
+	}
+	return nil
+}
+
+// verifyEndpoint checks that the certificate was issued for the endpoint host and the server application
+func verifyEndpoint(leaf *x509.Certificate, endpointURL string, applicationURI string) error {
+	u, err := url.Parse(endpointURL)
+	if err != nil {
+		return fmt.Errorf("invalid endpoint URL %s: %v", endpointURL, err)
+	}
+	if err := leaf.VerifyHostname(u.Hostname()); err != nil {
+		return fmt.Errorf("server certificate %s: %v", Thumbprint(leaf.Raw), err)
+	}
+
+	if applicationURI == "" || len(leaf.URIs) == 0 {
+		return nil
+	}
+	for _, uri := range leaf.URIs {
+		if uri.String() == applicationURI {
+			return nil
+		}
+	}
+	return fmt.Errorf("server certificate %s was not issued for application %s", Thumbprint(leaf.Raw), applicationURI)
+}
+
+// rejected copies an untrusted certificate to the rejected folder and returns err
+func (s *Store) rejected(leaf *x509.Certificate, err error) error {
+	if rejectErr := s.reject(leaf.Raw); rejectErr != nil {
+		return fmt.Errorf("%w; %v", err, rejectErr)
+	}
+	return err
+}
+
+// reject copies a certificate to the rejected folder
+func (s *Store) reject(der []byte) error {
+	path := filepath.Join(s.dir, RejectedDir, Thumbprint(der)+".der")
+	if _, err := os.Stat(path); err == nil {
+		return nil
+	}
+	return writeFile(path, der)
+}
+
+// readCertificates returns the certificates of a store folder. Files that
+// cannot be parsed are ignored; a missing folder is an empty one.
+func (s *Store) readCertificates(folder string) ([]*x509.Certificate, error) {
+	dir := filepath.Join(s.dir, folder)
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %v", dir, err)
+	}
+
+	var certs []*x509.Certificate
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		b, err := os.ReadFile(filepath.Join(dir, e.Name())) // #nosec G304 -- path is built from the configured PKI directory
+		if err != nil {
+			continue
+		}
+		parsed, err := parseCertificates(b)
+		if err != nil {
+			continue
+		}
+		certs = append(certs, parsed...)
+	}
+	return certs, nil
+}
+
+// parseCertificates parses one or more PEM or DER encoded certificates
+func parseCertificates(b []byte) ([]*x509.Certificate, error) {
+	var certs []*x509.Certificate
+	rest := b
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		c, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		certs = append(certs, c)
+	}
+	if len(certs) > 0 {
+		return certs, nil
+	}
+
+	certs, err := x509.ParseCertificates(b)
+	if err != nil {
+		return nil, err
+	}
+	if len(certs) == 0 {
+		return nil, errors.New("no certificate found")
+	}
+	return certs, nil
+}
@@ -0,0 +1,173 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2022 Schneider Electric
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package pki
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"math/big"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+const (
+	testEndpoint  = "opc.tcp://plc.local:4840"
+	testServerURI = "urn:plc:server"
+)
+
+type testCert struct {
+	der  []byte
+	cert *x509.Certificate
+	key  *rsa.PrivateKey
+}
+
+// newTestCert issues a certificate for the test server, signed by parent or self-signed when parent is nil
+func newTestCert(t *testing.T, isCA bool, parent *testCert, notAfter time.Time) *testCert {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	uri, _ := url.Parse(testServerURI)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: "test"},
+		NotBefore:             time.Now().Add(-2 * time.Hour),
+		NotAfter:              notAfter,
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  isCA,
+	}
+	if !isCA {
+		template.DNSNames = []string{"plc.local"}
+		template.URIs = []*url.URL{uri}
+	}
+
+	signer, signerKey := template, key
+	if parent != nil {
+		signer, signerKey = parent.cert, parent.key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, signer, &key.PublicKey, signerKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, _ := x509.ParseCertificate(der)
+	return &testCert{der: der, cert: cert, key: key}
+}
+
+func TestStore_VerifyServerCertificate(t *testing.T) {
+	valid := time.Now().Add(time.Hour)
+	ca := newTestCert(t, true, nil, valid)
+	signed := newTestCert(t, false, ca, valid)
+	selfSigned := newTestCert(t, false, nil, valid)
+	expired := newTestCert(t, false, nil, time.Now().Add(-time.Hour))
+
+	tests := []struct {
+		name           string
+		trusted        []*testCert
+		chain          []byte
+		endpoint       string
+		applicationURI string
+		wantErr        bool
+		wantUntrusted  bool
+		wantRejected   bool
+	}{
+		{
+			name:          "NOK - unknown self-signed certificate",
+			chain:         selfSigned.der,
+			endpoint:      testEndpoint,
+			wantErr:       true,
+			wantUntrusted: true,
+			wantRejected:  true,
+		},
+		{
+			name:     "OK - trusted self-signed certificate",
+			trusted:  []*testCert{selfSigned},
+			chain:    selfSigned.der,
+			endpoint: testEndpoint,
+		},
+		{
+			name:           "OK - certificate signed by trusted CA",
+			trusted:        []*testCert{ca},
+			chain:          signed.der,
+			endpoint:       testEndpoint,
+			applicationURI: testServerURI,
+		},
+		{
+			name:     "NOK - expired certificate",
+			trusted:  []*testCert{expired},
+			chain:    expired.der,
+			endpoint: testEndpoint,
+			wantErr:  true,
+		},
+		{
+			name:         "NOK - untrusted expired certificate",
+			chain:        expired.der,
+			endpoint:     testEndpoint,
+			wantErr:      true,
+			wantRejected: true,
+		},
+		{
+			name:     "NOK - hostname mismatch",
+			trusted:  []*testCert{selfSigned},
+			chain:    selfSigned.der,
+			endpoint: "opc.tcp://other.local:4840",
+			wantErr:  true,
+		},
+		{
+			name:           "NOK - application URI mismatch",
+			trusted:        []*testCert{selfSigned},
+			chain:          selfSigned.der,
+			endpoint:       testEndpoint,
+			applicationURI: "urn:other",
+			wantErr:        true,
+		},
+		{
+			name:     "NOK - invalid certificate",
+			chain:    []byte("foobar"),
+			endpoint: testEndpoint,
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for _, c := range tt.trusted {
+				if err := writeFile(filepath.Join(dir, TrustedDir, Thumbprint(c.der)+".der"), c.der); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			err := NewStore(dir, testURI).VerifyServerCertificate(tt.chain, tt.endpoint, tt.applicationURI)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Store.VerifyServerCertificate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if errors.Is(err, ErrCertificateUntrusted) != tt.wantUntrusted {
+				t.Errorf("Store.VerifyServerCertificate() error = %v, wantUntrusted %v", err, tt.wantUntrusted)
+			}
+			_, statErr := os.Stat(filepath.Join(dir, RejectedDir, Thumbprint(tt.chain)+".der"))
+			if (statErr == nil) != tt.wantRejected {
+				t.Errorf("certificate copied to rejected = %v, wantRejected %v", statErr == nil, tt.wantRejected)
+			}
+		})
+	}
+}
+
+func Test_parseCertificates(t *testing.T) {
+	c := newTestCert(t, false, nil, time.Now().Add(time.Hour))
+	chain := append(append([]byte{}, c.der...), c.der...)
+
+	certs, err := parseCertificates(chain)
+	if err != nil || len(certs) != 2 {
+		t.Errorf("expected a chain of two certificates, got %d, %v", len(certs), err)
+	}
+}
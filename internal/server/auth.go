@@ -0,0 +1,96 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2022 Schneider Electric
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"crypto/rsa"
+	"fmt"
+	"os"
+
+	"github.com/gopcua/opcua"
+	"github.com/gopcua/opcua/ua"
+)
+
+const (
+	UsernameKey    string = "username"
+	PasswordKey    string = "password"
+	CertificateKey string = "certificate"
+	PrivateKeyKey  string = "privateKey"
+)
+
+// authOptions returns the client options and the user token type used to
+// activate the session, based on the credentials configured for the device
+func (s *Server) authOptions() ([]opcua.Option, ua.UserTokenType, error) {
+	switch {
+	case s.config.UserCertFile != "" || s.config.UserCertSecretName != "":
+		cert, key, err := s.userCertificate()
+		if err != nil {
+			return nil, 0, fmt.Errorf("[%s] failed to load user certificate: %v", s.deviceName, err)
+		}
+		return []opcua.Option{opcua.AuthCertificate(cert), opcua.AuthPrivateKey(key)}, ua.UserTokenTypeCertificate, nil
+
+	case s.config.SecretName != "":
+		secrets, err := s.sdk.SecretProvider().GetSecret(s.config.SecretName, UsernameKey, PasswordKey)
+		if err != nil {
+			return nil, 0, fmt.Errorf("[%s] failed to get credentials from secret %s: %v", s.deviceName, s.config.SecretName, err)
+		}
+		return []opcua.Option{opcua.AuthUsername(secrets[UsernameKey], secrets[PasswordKey])}, ua.UserTokenTypeUserName, nil
+	}
+
+	return []opcua.Option{opcua.AuthAnonymous()}, ua.UserTokenTypeAnonymous, nil
+}
+
+// userCertificate loads the user identity certificate and its private key,
+// either from files or from the secret store
+func (s *Server) userCertificate() ([]byte, *rsa.PrivateKey, error) {
+	if s.config.UserCertFile == "" {
+		return s.secretCertificate(s.config.UserCertSecretName, s.config.UserKeySecretName)
+	}
+
+	certBytes, err := os.ReadFile(s.config.UserCertFile)
+	if err != nil {
+		return nil, nil, err
+	}
+	keyBytes, err := os.ReadFile(s.config.UserKeyFile)
+	if err != nil {
+		return nil, nil, err
+	}
+	return decodeKeyPair(certBytes, keyBytes)
+}
+
+// secretCertificate loads a PEM encoded certificate and private key from the secret store.
+// Both may be held by the same secret.
+func (s *Server) secretCertificate(certSecretName string, keySecretName string) ([]byte, *rsa.PrivateKey, error) {
+	certBytes, err := s.secretValue(certSecretName, CertificateKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	keyBytes, err := s.secretValue(keySecretName, PrivateKeyKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	return decodeKeyPair(certBytes, keyBytes)
+}
+
+// secretValue reads a single key from a secret in the secret store
+func (s *Server) secretValue(secretName string, key string) ([]byte, error) {
+	secrets, err := s.sdk.SecretProvider().GetSecret(secretName, key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s from secret %s: %v", key, secretName, err)
+	}
+	return []byte(secrets[key]), nil
+}
+
+// supportsUserTokenType checks whether the endpoint accepts the given user token type
+func supportsUserTokenType(ep *ua.EndpointDescription, tokenType ua.UserTokenType) bool {
+	for _, t := range ep.UserIdentityTokens {
+		if t.TokenType == tokenType {
+			return true
+		}
+	}
+	return false
+}
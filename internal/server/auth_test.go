@@ -0,0 +1,126 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2022 Schneider Electric
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/edgexfoundry/device-opcua-go/internal/test"
+	bootstrapMocks "github.com/edgexfoundry/go-mod-bootstrap/v3/bootstrap/interfaces/mocks"
+	"github.com/gopcua/opcua/ua"
+)
+
+func TestServer_authOptions(t *testing.T) {
+	certPEM, keyPEM := newTestCertificate(t)
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "user.pem")
+	keyFile := filepath.Join(dir, "user.key")
+	if err := os.WriteFile(certFile, certPEM, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(keyFile, keyPEM, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		config    *Config
+		secrets   map[string]string
+		secretErr error
+		certs     map[string]string
+		want      ua.UserTokenType
+		wantErr   bool
+	}{
+		{
+			name:   "OK - anonymous when no secret configured",
+			config: &Config{},
+			want:   ua.UserTokenTypeAnonymous,
+		},
+		{
+			name:    "OK - username from secret store",
+			config:  &Config{SecretName: "opcua"},
+			secrets: map[string]string{UsernameKey: "user", PasswordKey: "pass"},
+			want:    ua.UserTokenTypeUserName,
+		},
+		{
+			name:      "NOK - secret not found",
+			config:    &Config{SecretName: "opcua"},
+			secretErr: fmt.Errorf("not found"),
+			wantErr:   true,
+		},
+		{
+			name:   "OK - user certificate from files",
+			config: &Config{UserCertFile: certFile, UserKeyFile: keyFile},
+			want:   ua.UserTokenTypeCertificate,
+		},
+		{
+			name:    "NOK - user certificate file missing",
+			config:  &Config{UserCertFile: filepath.Join(dir, "missing.pem"), UserKeyFile: keyFile},
+			wantErr: true,
+		},
+		{
+			name:   "OK - user certificate from secret store",
+			config: &Config{UserCertSecretName: "user-cert", UserKeySecretName: "user-cert"},
+			certs:  map[string]string{CertificateKey: string(certPEM), PrivateKeyKey: string(keyPEM)},
+			want:   ua.UserTokenTypeCertificate,
+		},
+		{
+			name:    "NOK - invalid user certificate in secret store",
+			config:  &Config{UserCertSecretName: "user-cert", UserKeySecretName: "user-cert"},
+			certs:   map[string]string{CertificateKey: "foobar", PrivateKeyKey: string(keyPEM)},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dsMock := test.NewDSMock(t)
+			if tt.config.SecretName != "" {
+				spMock := bootstrapMocks.NewSecretProvider(t)
+				spMock.On("GetSecret", tt.config.SecretName, UsernameKey, PasswordKey).Return(tt.secrets, tt.secretErr)
+				dsMock.On("SecretProvider").Return(spMock)
+			}
+			if tt.config.UserCertSecretName != "" {
+				spMock := bootstrapMocks.NewSecretProvider(t)
+				spMock.On("GetSecret", tt.config.UserCertSecretName, CertificateKey).Return(tt.certs, nil)
+				spMock.On("GetSecret", tt.config.UserKeySecretName, PrivateKeyKey).Return(tt.certs, nil).Maybe()
+				dsMock.On("SecretProvider").Return(spMock)
+			}
+
+			s := NewServer("Test", dsMock, nil)
+			s.config = tt.config
+			opts, got, err := s.authOptions()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Server.authOptions() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			if got != tt.want {
+				t.Errorf("Server.authOptions() token type = %v, want %v", got, tt.want)
+			}
+			if len(opts) == 0 {
+				t.Error("Server.authOptions() returned no options")
+			}
+		})
+	}
+}
+
+func Test_supportsUserTokenType(t *testing.T) {
+	ep := &ua.EndpointDescription{
+		UserIdentityTokens: []*ua.UserTokenPolicy{{TokenType: ua.UserTokenTypeUserName}},
+	}
+	if !supportsUserTokenType(ep, ua.UserTokenTypeUserName) {
+		t.Error("expected username token type to be supported")
+	}
+	if supportsUserTokenType(ep, ua.UserTokenTypeCertificate) {
+		t.Error("expected certificate token type not to be supported")
+	}
+}
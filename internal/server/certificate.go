@@ -0,0 +1,160 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2022 Schneider Electric
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+
+	"github.com/edgexfoundry/device-opcua-go/internal/pki"
+	"github.com/gopcua/opcua"
+	"github.com/gopcua/opcua/ua"
+)
+
+// certificateOptions returns the client options setting the application instance
+// certificate. The configured files or secrets take precedence; otherwise the
+// certificate shared through the PKI store is used whenever the connection is secured.
+func (s *Server) certificateOptions() ([]opcua.Option, error) {
+	if s.config.CertFile != "" && s.config.KeyFile != "" {
+		return []opcua.Option{
+			opcua.CertificateFile(s.config.CertFile),
+			opcua.PrivateKeyFile(s.config.KeyFile),
+		}, nil
+	}
+
+	if s.config.CertSecretName != "" && s.config.KeySecretName != "" {
+		cert, key, err := s.secretCertificate(s.config.CertSecretName, s.config.KeySecretName)
+		if err != nil {
+			return nil, fmt.Errorf("[%s] failed to load application certificate: %v", s.deviceName, err)
+		}
+		return []opcua.Option{opcua.Certificate(cert), opcua.PrivateKey(key)}, nil
+	}
+
+	if !isSecured(s.config) {
+		return nil, nil
+	}
+
+	if s.pki == nil {
+		return nil, fmt.Errorf("[%s] no application certificate configured and no PKI store available", s.deviceName)
+	}
+
+	cert, key, err := s.pki.ApplicationCertificate()
+	if err != nil {
+		return nil, fmt.Errorf("[%s] failed to get application certificate: %v", s.deviceName, err)
+	}
+
+	// the session announces the URI of the certificate's subject alternative name
+	return []opcua.Option{opcua.Certificate(cert), opcua.PrivateKey(key)}, nil
+}
+
+// verifyServerCertificate checks the certificate of a secured endpoint against the trust list
+func (s *Server) verifyServerCertificate(ep *ua.EndpointDescription) error {
+	if s.pki == nil || !isSecured(s.config) {
+		return nil
+	}
+
+	var applicationURI string
+	if ep.Server != nil {
+		applicationURI = ep.Server.ApplicationURI
+	}
+
+	err := s.pki.VerifyServerCertificate(ep.ServerCertificate, s.config.Endpoint, applicationURI)
+	if errors.Is(err, pki.ErrCertificateUntrusted) {
+		return fmt.Errorf("[%s] %v: approve it by moving it from %s to %s in the PKI directory", s.deviceName, err, pki.RejectedDir, pki.TrustedDir)
+	}
+	if err != nil {
+		return fmt.Errorf("[%s] server certificate rejected: %v", s.deviceName, err)
+	}
+	return nil
+}
+
+// UsesSecret checks whether the device configuration refers to the given secret
+func (s *Server) UsesSecret(secretName string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.config == nil {
+		return false
+	}
+	for _, name := range s.config.SecretNames() {
+		if name == secretName {
+			return true
+		}
+	}
+	return false
+}
+
+// UsesTrustList checks whether the device connects through a secured channel,
+// in which case the server certificate is checked against the trust list
+func (s *Server) UsesTrustList() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.config != nil && isSecured(s.config)
+}
+
+// isSecured checks whether the configuration requests a signed or encrypted channel
+func isSecured(cfg *Config) bool {
+	policy := ua.FormatSecurityPolicyURI(cfg.Policy)
+	mode := ua.MessageSecurityModeFromString(cfg.Mode)
+	return (policy != "" && policy != ua.SecurityPolicyURINone) ||
+		(mode != ua.MessageSecurityModeInvalid && mode != ua.MessageSecurityModeNone)
+}
+
+// decodeKeyPair decodes a certificate and its private key
+func decodeKeyPair(certBytes []byte, keyBytes []byte) ([]byte, *rsa.PrivateKey, error) {
+	cert, err := decodeCertificate(certBytes)
+	if err != nil {
+		return nil, nil, err
+	}
+	key, err := decodePrivateKey(keyBytes)
+	if err != nil {
+		return nil, nil, err
+	}
+	return cert, key, nil
+}
+
+// decodeCertificate returns the DER bytes of a PEM or DER encoded certificate
+func decodeCertificate(b []byte) ([]byte, error) {
+	der := b
+	if block, _ := pem.Decode(b); block != nil {
+		if block.Type != "CERTIFICATE" {
+			return nil, fmt.Errorf("unexpected PEM block type %s for certificate", block.Type)
+		}
+		der = block.Bytes
+	}
+
+	if _, err := x509.ParseCertificate(der); err != nil {
+		return nil, fmt.Errorf("failed to parse certificate: %v", err)
+	}
+	return der, nil
+}
+
+// decodePrivateKey parses a PEM or DER encoded RSA private key in PKCS#1 or PKCS#8 form
+func decodePrivateKey(b []byte) (*rsa.PrivateKey, error) {
+	der := b
+	if block, _ := pem.Decode(b); block != nil {
+		der = block.Bytes
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(der); err == nil {
+		return key, nil
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(der)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse private key: %v", err)
+	}
+	rsaKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("private key is not an RSA key")
+	}
+	return rsaKey, nil
+}
@@ -0,0 +1,227 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2022 Schneider Electric
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/edgexfoundry/device-opcua-go/internal/pki"
+	"github.com/edgexfoundry/device-opcua-go/internal/test"
+	bootstrapMocks "github.com/edgexfoundry/go-mod-bootstrap/v3/bootstrap/interfaces/mocks"
+	"github.com/gopcua/opcua/ua"
+	"github.com/stretchr/testify/mock"
+)
+
+// newTestCertificate returns a PEM encoded self-signed certificate and its PKCS#1 private key
+func newTestCertificate(t *testing.T) ([]byte, []byte) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("unable to generate key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "test"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("unable to create certificate: %v", err)
+	}
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	return certPEM, keyPEM
+}
+
+func Test_decodeCertificate(t *testing.T) {
+	certPEM, keyPEM := newTestCertificate(t)
+	block, _ := pem.Decode(certPEM)
+
+	tests := []struct {
+		name    string
+		input   []byte
+		wantErr bool
+	}{
+		{name: "OK - PEM", input: certPEM},
+		{name: "OK - DER", input: block.Bytes},
+		{name: "NOK - wrong PEM block", input: keyPEM, wantErr: true},
+		{name: "NOK - garbage", input: []byte("foobar"), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := decodeCertificate(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("decodeCertificate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_decodePrivateKey(t *testing.T) {
+	certPEM, keyPEM := newTestCertificate(t)
+	key, _ := decodePrivateKey(keyPEM)
+	pkcs8, _ := x509.MarshalPKCS8PrivateKey(key)
+
+	tests := []struct {
+		name    string
+		input   []byte
+		wantErr bool
+	}{
+		{name: "OK - PKCS#1 PEM", input: keyPEM},
+		{name: "OK - PKCS#8 PEM", input: pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: pkcs8})},
+		{name: "OK - PKCS#8 DER", input: pkcs8},
+		{name: "NOK - certificate", input: certPEM, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := decodePrivateKey(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("decodePrivateKey() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestServer_certificateOptions(t *testing.T) {
+	certPEM, keyPEM := newTestCertificate(t)
+	secrets := map[string]string{CertificateKey: string(certPEM), PrivateKeyKey: string(keyPEM)}
+
+	tests := []struct {
+		name     string
+		config   *Config
+		store    *pki.Store
+		secrets  map[string]string
+		wantOpts bool
+		wantErr  bool
+	}{
+		{
+			name:   "OK - no security",
+			config: &Config{Policy: "None", Mode: "None"},
+		},
+		{
+			name:     "OK - configured files",
+			config:   &Config{Policy: "Basic256Sha256", Mode: "Sign", CertFile: "cert.pem", KeyFile: "key.pem"},
+			wantOpts: true,
+		},
+		{
+			name:     "OK - certificate from secret store",
+			config:   &Config{Policy: "Basic256Sha256", Mode: "Sign", CertSecretName: "app-cert", KeySecretName: "app-cert"},
+			secrets:  secrets,
+			wantOpts: true,
+		},
+		{
+			name:    "NOK - invalid certificate in secret store",
+			config:  &Config{Policy: "Basic256Sha256", Mode: "Sign", CertSecretName: "app-cert", KeySecretName: "app-cert"},
+			secrets: map[string]string{CertificateKey: "foobar"},
+			wantErr: true,
+		},
+		{
+			name:     "OK - generated certificate",
+			config:   &Config{Policy: "Basic256Sha256", Mode: "SignAndEncrypt"},
+			store:    pki.NewStore(t.TempDir(), "urn:test"),
+			wantOpts: true,
+		},
+		{
+			name:    "NOK - no PKI store",
+			config:  &Config{Policy: "Basic256Sha256", Mode: "SignAndEncrypt"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dsMock := test.NewDSMock(t)
+			if tt.secrets != nil {
+				spMock := bootstrapMocks.NewSecretProvider(t)
+				spMock.On("GetSecret", "app-cert", mock.Anything).Return(tt.secrets, nil)
+				dsMock.On("SecretProvider").Return(spMock)
+			}
+			s := NewServer("Test", dsMock, tt.store)
+			s.config = tt.config
+			opts, err := s.certificateOptions()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Server.certificateOptions() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if (len(opts) > 0) != tt.wantOpts {
+				t.Errorf("Server.certificateOptions() = %v, wantOpts %v", opts, tt.wantOpts)
+			}
+		})
+	}
+}
+
+func TestServer_certificateOptions_persistedCertificate(t *testing.T) {
+	dir := t.TempDir()
+	if _, _, err := pki.NewStore(dir, "urn:old:edgexfoundry:device-opcua").ApplicationCertificate(); err != nil {
+		t.Fatalf("unable to generate the application certificate: %v", err)
+	}
+
+	// a restarted container may derive another default URI from its hostname
+	store := pki.NewStore(dir, "urn:new:edgexfoundry:device-opcua")
+	s := NewServer("Test", test.NewDSMock(t), store)
+	s.config = &Config{Policy: "Basic256Sha256", Mode: "SignAndEncrypt"}
+
+	if _, err := s.certificateOptions(); err != nil {
+		t.Fatalf("Server.certificateOptions() error = %v", err)
+	}
+	der, _, err := store.ApplicationCertificate()
+	if err != nil {
+		t.Fatalf("unable to get the application certificate: %v", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("unable to parse the application certificate: %v", err)
+	}
+	if len(cert.URIs) != 1 || cert.URIs[0].String() != "urn:old:edgexfoundry:device-opcua" {
+		t.Errorf("certificate URIs = %v, want the persisted URI", cert.URIs)
+	}
+}
+
+func TestServer_verifyServerCertificate(t *testing.T) {
+	certPEM, _ := newTestCertificate(t)
+	der, _ := decodeCertificate(certPEM)
+	ep := &ua.EndpointDescription{ServerCertificate: der}
+
+	t.Run("OK - not secured", func(t *testing.T) {
+		s := NewServer("Test", test.NewDSMock(t), pki.NewStore(t.TempDir(), "urn:test"))
+		s.config = &Config{Endpoint: "opc.tcp://test", Policy: "None", Mode: "None"}
+		if err := s.verifyServerCertificate(ep); err != nil {
+			t.Errorf("expected no error, got = %v", err)
+		}
+	})
+
+	t.Run("NOK - untrusted certificate", func(t *testing.T) {
+		s := NewServer("Test", test.NewDSMock(t), pki.NewStore(t.TempDir(), "urn:test"))
+		s.config = &Config{Endpoint: "opc.tcp://test", Policy: "Basic256Sha256", Mode: "SignAndEncrypt"}
+		err := s.verifyServerCertificate(ep)
+		if err == nil || !strings.Contains(err.Error(), pki.RejectedDir) {
+			t.Errorf("expected untrusted certificate error, got = %v", err)
+		}
+	})
+}
+
+func TestServer_UsesSecret(t *testing.T) {
+	s := NewServer("Test", test.NewDSMock(t), nil)
+	if s.UsesSecret("app-cert") {
+		t.Error("expected no secret to be used before the configuration is loaded")
+	}
+
+	s.config = &Config{CertSecretName: "app-cert", KeySecretName: "app-key"}
+	if !s.UsesSecret("app-key") {
+		t.Error("expected key secret to be used")
+	}
+	if s.UsesSecret("opcua") {
+		t.Error("expected opcua secret not to be used")
+	}
+}
@@ -0,0 +1,72 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2022 Schneider Electric
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"fmt"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/models"
+	"github.com/gopcua/opcua/id"
+	"github.com/gopcua/opcua/ua"
+)
+
+// AcknowledgeCondition calls the standard Acknowledge method of a condition for the given event
+func (s *Server) AcknowledgeCondition(conditionID string, eventID []byte, comment string) error {
+	return s.callConditionMethod(id.AcknowledgeableConditionType_Acknowledge, conditionID, eventID, comment)
+}
+
+// ConfirmCondition calls the standard Confirm method of a condition for the given event
+func (s *Server) ConfirmCondition(conditionID string, eventID []byte, comment string) error {
+	return s.callConditionMethod(id.AcknowledgeableConditionType_Confirm, conditionID, eventID, comment)
+}
+
+// callConditionMethod calls a method of AcknowledgeableConditionType on a condition instance.
+// A Bad StatusCode returned by the server is wrapped in the error so that callers can match it.
+func (s *Server) callConditionMethod(methodID uint32, conditionID string, eventID []byte, comment string) error {
+	device, err := s.sdk.GetDeviceByName(s.deviceName)
+	if err != nil {
+		return fmt.Errorf("device not found: %v", err)
+	}
+	if device.AdminState == models.Locked || device.OperatingState == models.Down {
+		return fmt.Errorf("[%s] condition method not called: %w", s.deviceName, ErrDeviceUnavailable)
+	}
+
+	oid, err := ua.ParseNodeID(conditionID)
+	if err != nil {
+		return fmt.Errorf("[%s] invalid condition id %s: %w", s.deviceName, conditionID, ua.StatusBadNodeIDInvalid)
+	}
+
+	request := &ua.CallMethodRequest{
+		ObjectID: oid,
+		MethodID: ua.NewNumericNodeID(0, methodID),
+		InputArguments: []*ua.Variant{
+			ua.MustVariant(eventID),
+			ua.MustVariant(ua.NewLocalizedText(comment)),
+		},
+	}
+
+	client, err := s.connectedClient()
+	if err != nil {
+		return fmt.Errorf("[%s] client not initialized: %w", s.deviceName, err)
+	}
+
+	ctx, cancel := client.requestContext()
+	defer cancel()
+	resp, err := client.Call(ctx, request)
+	if err != nil {
+		return fmt.Errorf("[%s] condition method call failed: %w", s.deviceName, err)
+	}
+	if resp.StatusCode != ua.StatusOK {
+		return fmt.Errorf("[%s] condition method call on %s failed: %s: %w", s.deviceName, conditionID, statusCodeName(resp.StatusCode), resp.StatusCode)
+	}
+	for _, status := range resp.InputArgumentResults {
+		if status != ua.StatusOK {
+			return fmt.Errorf("[%s] condition method call on %s rejected an argument: %s: %w", s.deviceName, conditionID, statusCodeName(status), status)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2022 Schneider Electric
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/edgexfoundry/device-opcua-go/internal/test"
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/models"
+	"github.com/gopcua/opcua/ua"
+)
+
+func TestServer_callConditionMethod(t *testing.T) {
+	tests := []struct {
+		name        string
+		device      models.Device
+		conditionID string
+		want        error
+	}{
+		{
+			name:        "NOK - device locked",
+			device:      models.Device{AdminState: models.Locked, OperatingState: models.Up},
+			conditionID: "ns=2;s=Alarm",
+			want:        ErrDeviceUnavailable,
+		},
+		{
+			name:        "NOK - device down",
+			device:      models.Device{AdminState: models.Unlocked, OperatingState: models.Down},
+			conditionID: "ns=2;s=Alarm",
+			want:        ErrDeviceUnavailable,
+		},
+		{
+			name:        "NOK - invalid condition id",
+			device:      models.Device{AdminState: models.Unlocked, OperatingState: models.Up},
+			conditionID: "ns=x;i=1",
+			want:        ua.StatusBadNodeIDInvalid,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dsMock := test.NewDSMock(t)
+			dsMock.On("GetDeviceByName", "Test").Return(tt.device, nil)
+			s := NewServer("Test", dsMock, nil)
+			if err := s.AcknowledgeCondition(tt.conditionID, []byte{1, 2}, "comment"); !errors.Is(err, tt.want) {
+				t.Errorf("Server.AcknowledgeCondition() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
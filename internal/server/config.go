@@ -8,19 +8,148 @@ package server
 
 import (
 	"encoding/json"
+	"fmt"
+	"math"
+	"time"
 
 	"github.com/edgexfoundry/go-mod-core-contracts/v3/models"
 	"github.com/go-playground/validator/v10"
+	"github.com/gopcua/opcua"
+	"github.com/spf13/cast"
 )
 
 // Config struct details for OPCUA device list protocol properties
 type Config struct {
-	Endpoint  string   `json:"Endpoint" validate:"required"`
-	Policy    string   `json:"Policy" validate:"oneof=None Basic128Rsa15 Basic256 Basic256Sha256 Aes128Sha256RsaOaep Aes256Sha256RsaPss"`
-	Mode      string   `json:"Mode" validate:"oneof=None Sign SignAndEncrypt"`
-	CertFile  string   `json:"CertFile" validate:"required_unless=Policy None Mode None"`
-	KeyFile   string   `json:"KeyFile" validate:"required_unless=Policy None Mode None"`
-	Resources []string `json:"Resources"`
+	Endpoint           string   `json:"Endpoint" validate:"required"`
+	Policy             string   `json:"Policy" validate:"oneof=None Basic128Rsa15 Basic256 Basic256Sha256 Aes128Sha256RsaOaep Aes256Sha256RsaPss"`
+	Mode               string   `json:"Mode" validate:"oneof=None Sign SignAndEncrypt"`
+	CertFile           string   `json:"CertFile" validate:"required_with=KeyFile"`
+	KeyFile            string   `json:"KeyFile" validate:"required_with=CertFile"`
+	CertSecretName     string   `json:"CertSecretName" validate:"required_with=KeySecretName,excluded_with=CertFile"`
+	KeySecretName      string   `json:"KeySecretName" validate:"required_with=CertSecretName"`
+	SecretName         string   `json:"SecretName" validate:"excluded_with=UserCertFile UserCertSecretName"`
+	UserCertFile       string   `json:"UserCertFile" validate:"required_with=UserKeyFile,excluded_with=UserCertSecretName"`
+	UserKeyFile        string   `json:"UserKeyFile" validate:"required_with=UserCertFile"`
+	UserCertSecretName string   `json:"UserCertSecretName" validate:"required_with=UserKeySecretName"`
+	UserKeySecretName  string   `json:"UserKeySecretName" validate:"required_with=UserCertSecretName"`
+	Resources          []string `json:"Resources"`
+
+	ConnectTimeout        Duration `json:"ConnectTimeout" validate:"gte=0"`
+	RequestTimeout        Duration `json:"RequestTimeout" validate:"gte=0"`
+	SessionTimeout        Duration `json:"SessionTimeout" validate:"gte=0"`
+	SecureChannelLifetime Duration `json:"SecureChannelLifetime" validate:"gte=0"`
+
+	PublishingInterval         Duration `json:"PublishingInterval" validate:"gte=0"`
+	LifetimeCount              Count    `json:"LifetimeCount"`
+	MaxKeepAliveCount          Count    `json:"MaxKeepAliveCount"`
+	MaxNotificationsPerPublish Count    `json:"MaxNotificationsPerPublish"`
+	Priority                   Count    `json:"Priority" validate:"lte=255"`
+
+	TimestampSource    string   `json:"TimestampSource" validate:"omitempty,oneof=Source Server Local"`
+	QualityPolicy      string   `json:"QualityPolicy" validate:"omitempty,oneof=Drop Publish KeepLastGood"`
+	ReadFailure        string   `json:"ReadFailure" validate:"omitempty,oneof=Fail Partial"`
+	ReadingBatching    string   `json:"ReadingBatching" validate:"omitempty,oneof=Item Publish Window"`
+	ReadingBatchWindow Duration `json:"ReadingBatchWindow" validate:"gte=0"`
+	ReadingSourceName  string   `json:"ReadingSourceName"`
+}
+
+// ReadingBatching values, grouping the readings of the data changes into one event
+// per value, per publish response or per ReadingBatchWindow
+const (
+	batchPerItem    = "Item"
+	batchPerPublish = "Publish"
+	batchPerWindow  = "Window"
+)
+
+const (
+	defaultConnectTimeout     = 10 * time.Second
+	defaultRequestTimeout     = 10 * time.Second
+	defaultPublishingInterval = 500 * time.Millisecond
+	// the SDK drops the events of several readings without source name
+	defaultReadingSourceName = "DataChange"
+)
+
+// Duration is a time.Duration read from a protocol property such as "10s"
+type Duration time.Duration
+
+// UnmarshalJSON parses a duration string, an empty string meaning the default
+func (d *Duration) UnmarshalJSON(b []byte) error {
+	var v string
+	if err := json.Unmarshal(b, &v); err != nil {
+		return fmt.Errorf("duration must be a string such as \"10s\": %v", err)
+	}
+	if v == "" {
+		*d = 0
+		return nil
+	}
+	parsed, err := time.ParseDuration(v)
+	if err != nil {
+		return err
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+// MarshalJSON writes the duration in the form accepted by UnmarshalJSON
+func (d Duration) MarshalJSON() ([]byte, error) {
+	if d == 0 {
+		return json.Marshal("")
+	}
+	return json.Marshal(time.Duration(d).String())
+}
+
+// Count is an unsigned integer read from a protocol property given as a number or a string
+type Count uint32
+
+// UnmarshalJSON parses a number or a numeric string, an empty string meaning the default
+func (c *Count) UnmarshalJSON(b []byte) error {
+	var v any
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	if v == "" {
+		*c = 0
+		return nil
+	}
+	n, err := cast.ToFloat64E(v)
+	if err != nil || n < 0 || n > math.MaxUint32 || n != math.Trunc(n) {
+		return fmt.Errorf("%v is not a positive integer", v)
+	}
+	*c = Count(n)
+	return nil
+}
+
+// connectTimeout bounds the discovery of the endpoints and the opening of the session
+func (c *Config) connectTimeout() time.Duration {
+	if c.ConnectTimeout == 0 {
+		return defaultConnectTimeout
+	}
+	return time.Duration(c.ConnectTimeout)
+}
+
+// requestTimeout bounds every read, write, call and subscription request
+func (c *Config) requestTimeout() time.Duration {
+	if c.RequestTimeout == 0 {
+		return defaultRequestTimeout
+	}
+	return time.Duration(c.RequestTimeout)
+}
+
+// readingBatchWindow returns the interval at which batched readings are sent, 0 when
+// readings are not batched per window
+func (c *Config) readingBatchWindow() time.Duration {
+	if c.ReadingBatching != batchPerWindow {
+		return 0
+	}
+	return time.Duration(c.ReadingBatchWindow)
+}
+
+// readingSourceName returns the source name of the events batching several readings
+func (c *Config) readingSourceName() string {
+	if c == nil || c.ReadingSourceName == "" {
+		return defaultReadingSourceName
+	}
+	return c.ReadingSourceName
 }
 
 // NewConfig converts a properties map to a Config struct
@@ -37,8 +166,55 @@ func NewConfig(props models.ProtocolProperties) (*Config, error) {
 	return c, nil
 }
 
+// SecretNames returns the names of the secrets the configuration refers to
+func (c *Config) SecretNames() []string {
+	var names []string
+	for _, name := range []string{c.SecretName, c.CertSecretName, c.KeySecretName, c.UserCertSecretName, c.UserKeySecretName} {
+		if name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// subscriptionParameters returns the requested publishing parameters, the library
+// defaults applying to the ones left empty
+func (c *Config) subscriptionParameters() *opcua.SubscriptionParameters {
+	params := &opcua.SubscriptionParameters{
+		Interval:                   time.Duration(c.PublishingInterval),
+		LifetimeCount:              uint32(c.LifetimeCount),
+		MaxKeepAliveCount:          uint32(c.MaxKeepAliveCount),
+		MaxNotificationsPerPublish: uint32(c.MaxNotificationsPerPublish),
+		Priority:                   uint8(c.Priority),
+	}
+	if params.Interval == 0 {
+		params.Interval = defaultPublishingInterval
+	}
+	return params
+}
+
 // Validate makes sure the connection properties are valid
 func Validate(cfg *Config) error {
 	validate := validator.New()
-	return validate.Struct(cfg)
+	if err := validate.Struct(cfg); err != nil {
+		return err
+	}
+
+	// the server closes a subscription that missed LifetimeCount publishing intervals,
+	// which must cover at least three keep-alive intervals
+	lifetime, keepAlive := uint32(cfg.LifetimeCount), uint32(cfg.MaxKeepAliveCount)
+	if lifetime == 0 {
+		lifetime = opcua.DefaultSubscriptionLifetimeCount
+	}
+	if keepAlive == 0 {
+		keepAlive = opcua.DefaultSubscriptionMaxKeepAliveCount
+	}
+	if uint64(lifetime) < 3*uint64(keepAlive) {
+		return fmt.Errorf("LifetimeCount %d must be at least three times MaxKeepAliveCount %d", lifetime, keepAlive)
+	}
+
+	if cfg.ReadingBatching == batchPerWindow && cfg.ReadingBatchWindow == 0 {
+		return fmt.Errorf("ReadingBatching %s requires a ReadingBatchWindow", batchPerWindow)
+	}
+	return nil
 }
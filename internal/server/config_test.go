@@ -9,6 +9,7 @@ package server
 import (
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/edgexfoundry/go-mod-core-contracts/v3/models"
 )
@@ -27,6 +28,55 @@ func TestNewConfig(t *testing.T) {
 			want: &Config{
 				Endpoint: "opc.tcp://test", Policy: "None", Mode: "None", Resources: []string{"A", "B", "C"}, CertFile: "", KeyFile: ""},
 		},
+		{
+			name: "OK - secret name",
+			props: models.ProtocolProperties{
+				Endpoint: "opc.tcp://test", "Policy": "None", "Mode": "None", "SecretName": "opcua"},
+			want: &Config{
+				Endpoint: "opc.tcp://test", Policy: "None", Mode: "None", SecretName: "opcua"},
+		},
+		{
+			name: "OK - timeouts",
+			props: models.ProtocolProperties{
+				Endpoint: "opc.tcp://test", "Policy": "None", "Mode": "None", "ConnectTimeout": "5s", "RequestTimeout": "500ms",
+				"SessionTimeout": "", "SecureChannelLifetime": "1h"},
+			want: &Config{
+				Endpoint: "opc.tcp://test", Policy: "None", Mode: "None", ConnectTimeout: Duration(5 * time.Second),
+				RequestTimeout: Duration(500 * time.Millisecond), SecureChannelLifetime: Duration(time.Hour)},
+		},
+		{
+			name: "OK - subscription parameters",
+			props: models.ProtocolProperties{
+				Endpoint: "opc.tcp://test", "PublishingInterval": "1s", "LifetimeCount": "600", "MaxKeepAliveCount": 20,
+				"MaxNotificationsPerPublish": 1000.0, "Priority": ""},
+			want: &Config{
+				Endpoint: "opc.tcp://test", PublishingInterval: Duration(time.Second), LifetimeCount: 600, MaxKeepAliveCount: 20,
+				MaxNotificationsPerPublish: 1000},
+		},
+		{
+			name: "NOK - negative count",
+			props: models.ProtocolProperties{
+				Endpoint: "opc.tcp://test", "LifetimeCount": -1},
+			wantErr: true,
+		},
+		{
+			name: "NOK - count not a number",
+			props: models.ProtocolProperties{
+				Endpoint: "opc.tcp://test", "MaxKeepAliveCount": "many"},
+			wantErr: true,
+		},
+		{
+			name: "NOK - invalid timeout",
+			props: models.ProtocolProperties{
+				Endpoint: "opc.tcp://test", "RequestTimeout": "soon"},
+			wantErr: true,
+		},
+		{
+			name: "NOK - timeout not a string",
+			props: models.ProtocolProperties{
+				Endpoint: "opc.tcp://test", "RequestTimeout": 10},
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -65,14 +115,115 @@ func TestValidate(t *testing.T) {
 			wantErr: true,
 		},
 		{
-			name: "NOK - missing certfile or keyfile",
+			name: "NOK - negative timeout",
+			cfg: &Config{
+				Endpoint: "opc.tcp://test", Policy: "None", Mode: "None", RequestTimeout: Duration(-time.Second)},
+			wantErr: true,
+		},
+		{
+			name: "NOK - priority out of range",
+			cfg: &Config{
+				Endpoint: "opc.tcp://test", Policy: "None", Mode: "None", Priority: 256},
+			wantErr: true,
+		},
+		{
+			name: "NOK - lifetime count lower than three keep-alive counts",
+			cfg: &Config{
+				Endpoint: "opc.tcp://test", Policy: "None", Mode: "None", LifetimeCount: 50, MaxKeepAliveCount: 20},
+			wantErr: true,
+		},
+		{
+			name: "NOK - unknown reading batching",
+			cfg: &Config{
+				Endpoint: "opc.tcp://test", Policy: "None", Mode: "None", ReadingBatching: "Event"},
+			wantErr: true,
+		},
+		{
+			name: "NOK - unknown quality policy",
+			cfg: &Config{
+				Endpoint: "opc.tcp://test", Policy: "None", Mode: "None", QualityPolicy: "Ignore"},
+			wantErr: true,
+		},
+		{
+			name: "NOK - reading batch window missing",
+			cfg: &Config{
+				Endpoint: "opc.tcp://test", Policy: "None", Mode: "None", ReadingBatching: "Window"},
+			wantErr: true,
+		},
+		{
+			name: "OK - reading batch window",
+			cfg: &Config{
+				Endpoint: "opc.tcp://test", Policy: "None", Mode: "None", ReadingBatching: "Window", ReadingBatchWindow: Duration(time.Second)},
+		},
+		{
+			name: "NOK - keep-alive count above the default lifetime count",
+			cfg: &Config{
+				Endpoint: "opc.tcp://test", Policy: "None", Mode: "None", MaxKeepAliveCount: 5000},
+			wantErr: true,
+		},
+		{
+			name: "OK - subscription parameters",
+			cfg: &Config{
+				Endpoint: "opc.tcp://test", Policy: "None", Mode: "None", LifetimeCount: 60, MaxKeepAliveCount: 20, Priority: 255},
+		},
+		{
+			name: "NOK - certfile without keyfile",
+			cfg: &Config{
+				Endpoint: "opc.tcp://test",
+				Policy:   "Basic256",
+				Mode:     "Sign",
+				CertFile: "cert.pem",
+			},
+			wantErr: true,
+		},
+		{
+			name: "OK - missing certfile and keyfile uses generated certificate",
 			cfg: &Config{
 				Endpoint: "opc.tcp://test",
 				Policy:   "Basic256",
 				Mode:     "Sign",
 			},
+		},
+		{
+			name: "NOK - user certificate without key",
+			cfg: &Config{
+				Endpoint: "opc.tcp://test", Policy: "None", Mode: "None", UserCertFile: "user.pem"},
+			wantErr: true,
+		},
+		{
+			name: "NOK - user key secret without certificate secret",
+			cfg: &Config{
+				Endpoint: "opc.tcp://test", Policy: "None", Mode: "None", UserKeySecretName: "user-cert"},
+			wantErr: true,
+		},
+		{
+			name: "NOK - user certificate and username secret",
+			cfg: &Config{
+				Endpoint: "opc.tcp://test", Policy: "None", Mode: "None", UserCertFile: "user.pem", UserKeyFile: "user.key", SecretName: "opcua"},
 			wantErr: true,
 		},
+		{
+			name: "NOK - certificate secret without key secret",
+			cfg: &Config{
+				Endpoint: "opc.tcp://test", Policy: "Basic256Sha256", Mode: "Sign", CertSecretName: "app-cert"},
+			wantErr: true,
+		},
+		{
+			name: "NOK - certificate secret and certificate file",
+			cfg: &Config{
+				Endpoint: "opc.tcp://test", Policy: "Basic256Sha256", Mode: "Sign", CertSecretName: "app-cert", KeySecretName: "app-cert", CertFile: "cert.pem", KeyFile: "key.pem"},
+			wantErr: true,
+		},
+		{
+			name: "OK - certificate and key secrets",
+			cfg: &Config{
+				Endpoint: "opc.tcp://test", Policy: "Basic256Sha256", Mode: "Sign", CertSecretName: "app-cert", KeySecretName: "app-cert"},
+		},
+		{
+			name: "OK - user certificate and key",
+			cfg: &Config{
+				Endpoint: "opc.tcp://test", Policy: "None", Mode: "None", UserCertFile: "user.pem", UserKeyFile: "user.key"},
+		},
 		{
 			name: "OK - endpoint and resources",
 			cfg: &Config{
@@ -87,3 +238,36 @@ func TestValidate(t *testing.T) {
 		})
 	}
 }
+
+func TestConfig_SecretNames(t *testing.T) {
+	cfg := &Config{SecretName: "opcua", CertSecretName: "app-cert", KeySecretName: "app-key"}
+	want := []string{"opcua", "app-cert", "app-key"}
+	if got := cfg.SecretNames(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Config.SecretNames() = %v, want %v", got, want)
+	}
+}
+
+func TestConfig_timeouts(t *testing.T) {
+	cfg := &Config{}
+	if cfg.connectTimeout() != defaultConnectTimeout || cfg.requestTimeout() != defaultRequestTimeout {
+		t.Errorf("expected default timeouts, got %v and %v", cfg.connectTimeout(), cfg.requestTimeout())
+	}
+
+	cfg = &Config{ConnectTimeout: Duration(time.Second), RequestTimeout: Duration(2 * time.Second)}
+	if cfg.connectTimeout() != time.Second || cfg.requestTimeout() != 2*time.Second {
+		t.Errorf("expected configured timeouts, got %v and %v", cfg.connectTimeout(), cfg.requestTimeout())
+	}
+}
+
+func TestConfig_subscriptionParameters(t *testing.T) {
+	got := (&Config{}).subscriptionParameters()
+	if got.Interval != defaultPublishingInterval {
+		t.Errorf("expected default publishing interval %v, got %v", defaultPublishingInterval, got.Interval)
+	}
+
+	cfg := &Config{PublishingInterval: Duration(time.Second), LifetimeCount: 60, MaxKeepAliveCount: 20, MaxNotificationsPerPublish: 5, Priority: 7}
+	got = cfg.subscriptionParameters()
+	if got.Interval != time.Second || got.LifetimeCount != 60 || got.MaxKeepAliveCount != 20 || got.MaxNotificationsPerPublish != 5 || got.Priority != 7 {
+		t.Errorf("unexpected subscription parameters %+v", got)
+	}
+}
@@ -0,0 +1,214 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2022 Schneider Electric
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gopcua/opcua/id"
+	"github.com/gopcua/opcua/ua"
+	"github.com/spf13/cast"
+)
+
+const (
+	ConditionIDField string = "ConditionId"
+
+	minEventSeverity = 1
+	maxEventSeverity = 1000
+)
+
+// isEventResource checks whether a device resource monitors the events of an object
+func isEventResource(attrs map[string]any) bool {
+	_, ok := attrs[EVENTFIELDS]
+	return ok
+}
+
+// eventFields returns the browse paths of the event fields selected by a device resource
+func eventFields(attrs map[string]any) ([]string, error) {
+	fields, err := cast.ToStringSliceE(attrs[EVENTFIELDS])
+	if err != nil || len(fields) == 0 {
+		return nil, fmt.Errorf("invalid %s %v: must be a list of event field names", EVENTFIELDS, attrs[EVENTFIELDS])
+	}
+	for _, field := range fields {
+		if field == "" {
+			return nil, fmt.Errorf("invalid %s %v: empty event field name", EVENTFIELDS, attrs[EVENTFIELDS])
+		}
+	}
+	return fields, nil
+}
+
+// newEventFilter builds the filter selecting the event fields of a device resource,
+// restricted to the configured event types and minimum severity
+func newEventFilter(attrs map[string]any) (*ua.EventFilter, error) {
+	fields, err := eventFields(attrs)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := &ua.EventFilter{
+		SelectClauses: make([]*ua.SimpleAttributeOperand, len(fields)),
+		WhereClause:   &ua.ContentFilter{},
+	}
+	for i, field := range fields {
+		filter.SelectClauses[i] = eventFieldOperand(field)
+	}
+
+	var b contentFilterBuilder
+	var conditions []uint32
+
+	if v, ok := attrs[EVENTTYPES]; ok {
+		types, err := cast.ToStringSliceE(v)
+		if err != nil || len(types) == 0 {
+			return nil, fmt.Errorf("invalid %s %v: must be a list of event type node ids", EVENTTYPES, v)
+		}
+		var ofTypes []uint32
+		for _, t := range types {
+			typeID, err := ua.ParseNodeID(t)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s %s: %v", EVENTTYPES, t, err)
+			}
+			ofTypes = append(ofTypes, b.add(ua.FilterOperatorOfType, &ua.LiteralOperand{Value: ua.MustVariant(typeID)}))
+		}
+		conditions = append(conditions, b.combine(ua.FilterOperatorOr, ofTypes))
+	}
+
+	if v, ok := attrs[MINSEVERITY]; ok {
+		severity, err := cast.ToUint16E(v)
+		if err != nil || severity < minEventSeverity || severity > maxEventSeverity {
+			return nil, fmt.Errorf("invalid %s %v: must be between %d and %d", MINSEVERITY, v, minEventSeverity, maxEventSeverity)
+		}
+		conditions = append(conditions, b.add(ua.FilterOperatorGreaterThanOrEqual,
+			eventFieldOperand("Severity"), &ua.LiteralOperand{Value: ua.MustVariant(severity)}))
+	}
+
+	if len(conditions) > 0 {
+		b.combine(ua.FilterOperatorAnd, conditions)
+		filter.WhereClause.Elements = b.elements()
+	}
+
+	return filter, nil
+}
+
+// eventFieldOperand selects a field of BaseEventType, nested fields being separated by a slash.
+// ConditionId selects the node id of the condition raising the event.
+func eventFieldOperand(field string) *ua.SimpleAttributeOperand {
+	if field == ConditionIDField {
+		return &ua.SimpleAttributeOperand{
+			TypeDefinitionID: ua.NewNumericNodeID(0, id.ConditionType),
+			AttributeID:      ua.AttributeIDNodeID,
+		}
+	}
+
+	var path []*ua.QualifiedName
+	for _, name := range strings.Split(field, "/") {
+		path = append(path, &ua.QualifiedName{NamespaceIndex: 0, Name: name})
+	}
+	return &ua.SimpleAttributeOperand{
+		TypeDefinitionID: ua.NewNumericNodeID(0, id.BaseEventType),
+		BrowsePath:       path,
+		AttributeID:      ua.AttributeIDValue,
+	}
+}
+
+// contentFilterBuilder appends the elements of a content filter from the leaves to the root.
+// OPC UA expects the root first, so the element order is reversed once complete.
+type contentFilterBuilder struct {
+	list []*ua.ContentFilterElement
+}
+
+// add appends an element and returns its index, operands being either
+// filter operands or indexes of elements added before
+func (b *contentFilterBuilder) add(op ua.FilterOperator, operands ...any) uint32 {
+	element := &ua.ContentFilterElement{FilterOperator: op}
+	for _, operand := range operands {
+		if index, ok := operand.(uint32); ok {
+			operand = &ua.ElementOperand{Index: index}
+		}
+		element.FilterOperands = append(element.FilterOperands, ua.NewExtensionObject(operand))
+	}
+	b.list = append(b.list, element)
+	return uint32(len(b.list) - 1)
+}
+
+// combine joins the elements with a binary operator and returns the index of the result
+func (b *contentFilterBuilder) combine(op ua.FilterOperator, indexes []uint32) uint32 {
+	result := indexes[0]
+	for _, index := range indexes[1:] {
+		result = b.add(op, result, index)
+	}
+	return result
+}
+
+// elements returns the elements with the last added one as root, at index 0
+func (b *contentFilterBuilder) elements() []*ua.ContentFilterElement {
+	last := uint32(len(b.list) - 1)
+	elements := make([]*ua.ContentFilterElement, len(b.list))
+	for i, element := range b.list {
+		for _, operand := range element.FilterOperands {
+			if e, ok := operand.Value.(*ua.ElementOperand); ok {
+				e.Index = last - e.Index
+			}
+		}
+		elements[last-uint32(i)] = element
+	}
+	return elements
+}
+
+// handleEvents publishes each event as an Object reading holding the selected fields
+func (s *Server) handleEvents(enl *ua.EventNotificationList) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, event := range enl.Events {
+		resourceName := s.resourceMap[event.ClientHandle]
+		deviceResource, ok := s.sdk.DeviceResource(s.deviceName, resourceName)
+		if !ok {
+			s.sdk.LoggingClient().Errorf("[%s] Incoming event ignored. No DeviceObject found: deviceResource=%v", s.deviceName, resourceName)
+			continue
+		}
+		fields, err := eventFields(deviceResource.Attributes)
+		if err != nil {
+			s.sdk.LoggingClient().Errorf("[%s] Incoming event ignored. deviceResource=%v: %v", s.deviceName, resourceName, err)
+			continue
+		}
+
+		data := make(map[string]any, len(fields))
+		for i, field := range fields {
+			if i < len(event.EventFields) {
+				data[field] = eventFieldValue(event.EventFields[i])
+			}
+		}
+		if err := s.onIncomingDataReceived(data, resourceName); err != nil {
+			s.sdk.LoggingClient().Errorf("%v", err)
+		}
+	}
+}
+
+// eventFieldValue converts an event field into a value that serializes to readable JSON
+func eventFieldValue(v *ua.Variant) any {
+	if v == nil {
+		return nil
+	}
+	switch value := v.Value().(type) {
+	case *ua.NodeID:
+		return value.String()
+	case *ua.ExpandedNodeID:
+		return value.String()
+	case *ua.LocalizedText:
+		return value.Text
+	case *ua.QualifiedName:
+		return value.Name
+	case ua.StatusCode:
+		return statusCodeName(value)
+	case time.Time:
+		return value.UTC().Format(time.RFC3339Nano)
+	default:
+		return value
+	}
+}
@@ -0,0 +1,171 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2022 Schneider Electric
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/edgexfoundry/device-sdk-go/v3/pkg/interfaces/mocks"
+	sdkModels "github.com/edgexfoundry/device-sdk-go/v3/pkg/models"
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/clients/logger"
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/common"
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/models"
+	"github.com/gopcua/opcua/id"
+	"github.com/gopcua/opcua/ua"
+)
+
+func Test_newEventFilter(t *testing.T) {
+	tests := []struct {
+		name         string
+		attrs        map[string]any
+		wantSelect   int
+		wantElements []ua.FilterOperator
+		wantErr      bool
+	}{
+		{
+			name:       "OK - select clauses only",
+			attrs:      map[string]any{EVENTFIELDS: []any{"EventId", "Message", "EnabledState/Id"}},
+			wantSelect: 3,
+		},
+		{
+			name:         "OK - event type",
+			attrs:        map[string]any{EVENTFIELDS: []any{"EventId"}, EVENTTYPES: []any{"i=2915"}},
+			wantSelect:   1,
+			wantElements: []ua.FilterOperator{ua.FilterOperatorOfType},
+		},
+		{
+			name:       "OK - event types and severity",
+			attrs:      map[string]any{EVENTFIELDS: []string{"EventId", "Severity"}, EVENTTYPES: []string{"i=2915", "ns=2;i=5000"}, MINSEVERITY: "500"},
+			wantSelect: 2,
+			wantElements: []ua.FilterOperator{
+				ua.FilterOperatorAnd, ua.FilterOperatorGreaterThanOrEqual, ua.FilterOperatorOr, ua.FilterOperatorOfType, ua.FilterOperatorOfType,
+			},
+		},
+		{
+			name:    "NOK - no event fields",
+			attrs:   map[string]any{EVENTFIELDS: []any{}},
+			wantErr: true,
+		},
+		{
+			name:    "NOK - invalid event type",
+			attrs:   map[string]any{EVENTFIELDS: []any{"EventId"}, EVENTTYPES: []any{"ns=x;i=1"}},
+			wantErr: true,
+		},
+		{
+			name:    "NOK - severity out of range",
+			attrs:   map[string]any{EVENTFIELDS: []any{"EventId"}, MINSEVERITY: 1001},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := newEventFilter(tt.attrs)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("newEventFilter() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			if len(got.SelectClauses) != tt.wantSelect {
+				t.Errorf("newEventFilter() returned %d select clauses, want %d", len(got.SelectClauses), tt.wantSelect)
+			}
+			if len(got.WhereClause.Elements) != len(tt.wantElements) {
+				t.Fatalf("newEventFilter() returned %d where elements, want %d", len(got.WhereClause.Elements), len(tt.wantElements))
+			}
+			for i, element := range got.WhereClause.Elements {
+				if element.FilterOperator != tt.wantElements[i] {
+					t.Errorf("element %d operator = %v, want %v", i, element.FilterOperator, tt.wantElements[i])
+				}
+				// operands must refer to elements after the current one
+				for _, operand := range element.FilterOperands {
+					if e, ok := operand.Value.(*ua.ElementOperand); ok && int(e.Index) <= i {
+						t.Errorf("element %d refers to element %d", i, e.Index)
+					}
+				}
+			}
+		})
+	}
+}
+
+func Test_eventFieldOperand(t *testing.T) {
+	op := eventFieldOperand("EnabledState/Id")
+	if len(op.BrowsePath) != 2 || op.BrowsePath[1].Name != "Id" || op.AttributeID != ua.AttributeIDValue {
+		t.Errorf("unexpected operand %+v", op)
+	}
+
+	op = eventFieldOperand(ConditionIDField)
+	if len(op.BrowsePath) != 0 || op.AttributeID != ua.AttributeIDNodeID || op.TypeDefinitionID.IntID() != id.ConditionType {
+		t.Errorf("unexpected condition id operand %+v", op)
+	}
+}
+
+func Test_eventFieldValue(t *testing.T) {
+	now := time.Date(2022, 1, 2, 3, 4, 5, 0, time.UTC)
+	tests := []struct {
+		name  string
+		input *ua.Variant
+		want  any
+	}{
+		{name: "nil", input: nil, want: nil},
+		{name: "node id", input: ua.MustVariant(ua.NewNumericNodeID(2, 42)), want: "ns=2;i=42"},
+		{name: "localized text", input: ua.MustVariant(&ua.LocalizedText{Text: "High level"}), want: "High level"},
+		{name: "time", input: ua.MustVariant(now), want: "2022-01-02T03:04:05Z"},
+		{name: "status code", input: ua.MustVariant(ua.StatusBadNodeIDUnknown), want: "BadNodeIDUnknown"},
+		{name: "number", input: ua.MustVariant(uint16(500)), want: uint16(500)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := eventFieldValue(tt.input); got != tt.want {
+				t.Errorf("eventFieldValue() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestServer_handleEvents(t *testing.T) {
+	ch := make(chan *sdkModels.AsyncValues, 1)
+	dsMock := mocks.NewDeviceServiceSDK(t)
+	dsMock.On("LoggingClient").Return(logger.NewMockClient()).Maybe()
+	dsMock.On("AsyncValuesChannel").Return(ch)
+	dsMock.On("DeviceResource", "Test", "Alarms").Return(models.DeviceResource{
+		Name:       "Alarms",
+		Properties: models.ResourceProperties{ValueType: common.ValueTypeObject},
+		Attributes: map[string]any{NODE: "i=2253", EVENTFIELDS: []any{"Message", "Severity"}},
+	}, true)
+
+	s := NewServer("Test", dsMock, nil)
+	s.resourceMap[42] = "Alarms"
+	s.handleEvents(&ua.EventNotificationList{Events: []*ua.EventFieldList{{
+		ClientHandle: 42,
+		EventFields:  []*ua.Variant{ua.MustVariant(&ua.LocalizedText{Text: "High level"}), ua.MustVariant(uint16(700))},
+	}}})
+
+	values := <-ch
+	fields, ok := values.CommandValues[0].Value.(map[string]any)
+	if !ok || fields["Message"] != "High level" || fields["Severity"] != uint16(700) {
+		t.Errorf("unexpected event reading %+v", values.CommandValues[0].Value)
+	}
+}
+
+func TestValidateResource_events(t *testing.T) {
+	resource := models.DeviceResource{Name: "Alarms", Attributes: map[string]any{EVENTFIELDS: []any{"EventId"}}}
+	if err := ValidateResource(resource); err == nil {
+		t.Error("expected an error for an event resource not of type Object")
+	}
+
+	resource.Properties.ValueType = common.ValueTypeObject
+	if err := ValidateResource(resource); err != nil {
+		t.Errorf("expected no error, got = %v", err)
+	}
+
+	resource.Attributes[DEADBANDTYPE] = "absolute"
+	if err := ValidateResource(resource); err == nil {
+		t.Error("expected an error for a deadband on an event resource")
+	}
+}
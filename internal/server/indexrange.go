@@ -0,0 +1,117 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2022 Schneider Electric
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/common"
+	"github.com/gopcua/opcua/ua"
+	"github.com/spf13/cast"
+)
+
+// indexRange reads the NumericRange selecting the array elements of a device resource,
+// such as "2", "0:9" or "0:1,2:3" for a two-dimensional array. It returns the range
+// and the number of elements it selects in each dimension, or an empty range when unset.
+func indexRange(attrs map[string]any) (string, []int, error) {
+	v, ok := attrs[INDEXRANGE]
+	if !ok {
+		return "", nil, nil
+	}
+	r, err := cast.ToStringE(v)
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid %s %v: %v", INDEXRANGE, v, err)
+	}
+	dims, err := parseIndexRange(r)
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid %s %q: %v", INDEXRANGE, r, err)
+	}
+	return r, dims, nil
+}
+
+// parseIndexRange checks the syntax of a NumericRange and returns the length of each dimension
+func parseIndexRange(r string) ([]int, error) {
+	var dims []int
+	for _, dim := range strings.Split(r, ",") {
+		bounds := strings.Split(dim, ":")
+		if len(bounds) > 2 {
+			return nil, fmt.Errorf("dimension %q must be an index or a min:max range", dim)
+		}
+		var values []int
+		for _, bound := range bounds {
+			i, err := strconv.ParseUint(bound, 10, 31)
+			if err != nil {
+				return nil, fmt.Errorf("dimension %q must be an index or a min:max range", dim)
+			}
+			values = append(values, int(i))
+		}
+		if len(values) == 1 {
+			dims = append(dims, 1)
+			continue
+		}
+		if values[0] >= values[1] {
+			return nil, fmt.Errorf("dimension %q: min must be lower than max", dim)
+		}
+		dims = append(dims, values[1]-values[0]+1)
+	}
+	return dims, nil
+}
+
+// shapeValue arranges a value written to a range into the dimensions of the range,
+// a scalar value being written as a single element
+func shapeValue(value any, dims []int) (any, error) {
+	v := reflect.ValueOf(value)
+	if v.Kind() != reflect.Slice {
+		element := reflect.MakeSlice(reflect.SliceOf(v.Type()), 1, 1)
+		element.Index(0).Set(v)
+		v = element
+	}
+	// the rows of a multidimensional Byte array are not ByteStrings either
+	if b, ok := v.Interface().([]uint8); ok {
+		v = reflect.ValueOf(ua.ByteArray(b))
+	}
+	count := 1
+	for _, dim := range dims {
+		count *= dim
+	}
+	if v.Len() != count {
+		return nil, fmt.Errorf("the %s selects %d elements, got %d", INDEXRANGE, count, v.Len())
+	}
+	return reshape(v, dims).Interface(), nil
+}
+
+// reshape splits a flat slice into nested slices, in row-major order
+func reshape(v reflect.Value, dims []int) reflect.Value {
+	if len(dims) == 1 {
+		return v
+	}
+	typ := v.Type()
+	for range dims[1:] {
+		typ = reflect.SliceOf(typ)
+	}
+	step := v.Len() / dims[0]
+	out := reflect.MakeSlice(typ, dims[0], dims[0])
+	for i := 0; i < dims[0]; i++ {
+		out.Index(i).Set(reshape(v.Slice(i*step, (i+1)*step), dims[1:]))
+	}
+	return out
+}
+
+// rangeElement unwraps the single element read from the range of a scalar resource
+func rangeElement(valueType string, data any) any {
+	if strings.HasSuffix(valueType, "Array") || valueType == common.ValueTypeObject {
+		return data
+	}
+	v := reflect.ValueOf(data)
+	for v.Kind() == reflect.Slice && v.Len() == 1 {
+		v = v.Index(0)
+	}
+	return v.Interface()
+}
@@ -0,0 +1,95 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2022 Schneider Electric
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/common"
+	"github.com/gopcua/opcua/ua"
+)
+
+func Test_indexRange(t *testing.T) {
+	tests := []struct {
+		name     string
+		attrs    map[string]any
+		want     string
+		wantDims []int
+		wantErr  bool
+	}{
+		{name: "OK - unset"},
+		{name: "OK - single index", attrs: map[string]any{INDEXRANGE: "2"}, want: "2", wantDims: []int{1}},
+		{name: "OK - range", attrs: map[string]any{INDEXRANGE: "0:9"}, want: "0:9", wantDims: []int{10}},
+		{name: "OK - multidimensional range", attrs: map[string]any{INDEXRANGE: "1,0:2"}, want: "1,0:2", wantDims: []int{1, 3}},
+		{name: "NOK - empty", attrs: map[string]any{INDEXRANGE: ""}, wantErr: true},
+		{name: "NOK - min not lower than max", attrs: map[string]any{INDEXRANGE: "3:3"}, wantErr: true},
+		{name: "NOK - negative index", attrs: map[string]any{INDEXRANGE: "-1"}, wantErr: true},
+		{name: "NOK - too many bounds", attrs: map[string]any{INDEXRANGE: "0:1:2"}, wantErr: true},
+		{name: "NOK - empty dimension", attrs: map[string]any{INDEXRANGE: "0:1,"}, wantErr: true},
+		{name: "NOK - not a string", attrs: map[string]any{INDEXRANGE: []int{1}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, dims, err := indexRange(tt.attrs)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("indexRange() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want || !reflect.DeepEqual(dims, tt.wantDims) {
+				t.Errorf("indexRange() = %q %v, want %q %v", got, dims, tt.want, tt.wantDims)
+			}
+		})
+	}
+}
+
+func Test_shapeValue(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   any
+		dims    []int
+		want    any
+		wantErr bool
+	}{
+		{name: "OK - array", value: []int32{1, 2, 3}, dims: []int{3}, want: []int32{1, 2, 3}},
+		{name: "OK - scalar", value: float64(1.5), dims: []int{1}, want: []float64{1.5}},
+		{name: "OK - two dimensions", value: []int32{1, 2, 3, 4, 5, 6}, dims: []int{2, 3}, want: [][]int32{{1, 2, 3}, {4, 5, 6}}},
+		{name: "OK - bytes", value: ua.ByteArray{1, 2}, dims: []int{1, 2}, want: []ua.ByteArray{{1, 2}}},
+		{name: "OK - byte", value: uint8(7), dims: []int{1}, want: ua.ByteArray{7}},
+		{name: "OK - byte rows", value: []uint8{1, 2, 3, 4}, dims: []int{2, 2}, want: []ua.ByteArray{{1, 2}, {3, 4}}},
+		{name: "NOK - element count mismatch", value: []int32{1, 2, 3}, dims: []int{2, 2}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := shapeValue(tt.value, tt.dims)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("shapeValue() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("shapeValue() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// the shaped value encodes as a multidimensional array
+	value, _ := shapeValue([]int32{1, 2, 3, 4}, []int{2, 2})
+	v, err := ua.NewVariant(value)
+	if err != nil || !reflect.DeepEqual(v.ArrayDimensions(), []int32{2, 2}) {
+		t.Errorf("expected a 2x2 variant, got %v: %v", v, err)
+	}
+}
+
+func Test_rangeElement(t *testing.T) {
+	if got := rangeElement(common.ValueTypeFloat64, []float64{1.5}); got != 1.5 {
+		t.Errorf("expected the single element, got %v", got)
+	}
+	if got := rangeElement(common.ValueTypeInt32, [][]int32{{7}}); got != int32(7) {
+		t.Errorf("expected the single element of a multidimensional range, got %v", got)
+	}
+	if got := rangeElement(common.ValueTypeFloat64Array, []float64{1.5}); !reflect.DeepEqual(got, []float64{1.5}) {
+		t.Errorf("expected the array, got %v", got)
+	}
+}
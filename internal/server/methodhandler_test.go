@@ -164,7 +164,7 @@ func TestDriver_ProcessMethodCall(t *testing.T) {
 			}
 
 			dsMock := test.NewDSMock(t)
-			s := NewServer("test", dsMock)
+			s := NewServer("test", dsMock, nil)
 			dsMock.On("GetDeviceByName", mock.Anything).Return(tt.args.device, tt.deviceErr).Times(1)
 			if tt.deviceErr == nil && tt.args.device.AdminState != models.Locked && tt.args.device.OperatingState != models.Down {
 				dsMock.On("DeviceResource", mock.Anything, tt.args.method).Return(tt.args.resource, tt.args.resource.Name != "")
@@ -173,7 +173,7 @@ func TestDriver_ProcessMethodCall(t *testing.T) {
 				s.client = nil
 				dsMock.On("GetDeviceByName", mock.Anything).Return(models.Device{}, fmt.Errorf("error")).Times(1)
 			} else {
-				s.client = &Client{client, context.Background()}
+				s.client = &Client{Client: client, ctx: context.Background(), timeout: defaultRequestTimeout}
 			}
 			got, err := s.ProcessMethodCall(tt.args.method, tt.args.parameters)
 			if (err != nil) != tt.wantErr {
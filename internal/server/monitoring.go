@@ -0,0 +1,250 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2022 Schneider Electric
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/common"
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/models"
+	"github.com/gopcua/opcua/ua"
+	"github.com/spf13/cast"
+)
+
+const (
+	defaultQueueSize     uint32 = 10
+	defaultDiscardOldest bool   = true
+)
+
+var deadbandTypes = map[string]ua.DeadbandType{
+	"absolute": ua.DeadbandTypeAbsolute,
+	"percent":  ua.DeadbandTypePercent,
+}
+
+var dataChangeTriggers = map[string]ua.DataChangeTrigger{
+	"Status":               ua.DataChangeTriggerStatus,
+	"StatusValue":          ua.DataChangeTriggerStatusValue,
+	"StatusValueTimestamp": ua.DataChangeTriggerStatusValueTimestamp,
+}
+
+// monitoringParameters holds the sampling settings of a monitored item,
+// read from the attributes of its device resource
+type monitoringParameters struct {
+	samplingInterval float64
+	queueSize        uint32
+	discardOldest    bool
+	indexRange       string
+	// filter is either a *ua.DataChangeFilter or, for event resources, a *ua.EventFilter
+	filter any
+}
+
+// newMonitoringParameters reads the sampling attributes, falling back to the library defaults
+func newMonitoringParameters(attrs map[string]any) (*monitoringParameters, error) {
+	params := &monitoringParameters{
+		queueSize:     defaultQueueSize,
+		discardOldest: defaultDiscardOldest,
+	}
+
+	if v, ok := attrs[SAMPLINGINTERVAL]; ok {
+		interval, err := cast.ToFloat64E(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s %v: %v", SAMPLINGINTERVAL, v, err)
+		}
+		// -1 requests the publishing interval of the subscription
+		if interval < 0 && interval != -1 {
+			return nil, fmt.Errorf("invalid %s %v: must be -1 or a positive number of milliseconds", SAMPLINGINTERVAL, v)
+		}
+		params.samplingInterval = interval
+	}
+
+	if v, ok := attrs[QUEUESIZE]; ok {
+		size, err := cast.ToFloat64E(v)
+		if err != nil || size < 0 || size > math.MaxUint32 || size != math.Trunc(size) {
+			return nil, fmt.Errorf("invalid %s %v: must be a positive integer", QUEUESIZE, v)
+		}
+		params.queueSize = uint32(size)
+	}
+
+	if v, ok := attrs[DISCARDOLDEST]; ok {
+		discard, err := cast.ToBoolE(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s %v: %v", DISCARDOLDEST, v, err)
+		}
+		params.discardOldest = discard
+	}
+
+	if isEventResource(attrs) {
+		for _, attr := range []string{DEADBANDTYPE, DEADBANDVALUE, DATACHANGETRIGGER, INDEXRANGE} {
+			if _, ok := attrs[attr]; ok {
+				return nil, fmt.Errorf("%s does not apply to an event resource", attr)
+			}
+		}
+		filter, err := newEventFilter(attrs)
+		if err != nil {
+			return nil, err
+		}
+		params.filter = filter
+		return params, nil
+	}
+
+	r, _, err := indexRange(attrs)
+	if err != nil {
+		return nil, err
+	}
+	params.indexRange = r
+
+	filter, err := newDataChangeFilter(attrs)
+	if err != nil {
+		return nil, err
+	}
+	if filter != nil {
+		params.filter = filter
+	}
+
+	return params, nil
+}
+
+// newDataChangeFilter reads the deadband and trigger attributes. No filter is
+// returned when none is set, leaving the server default StatusValue trigger.
+func newDataChangeFilter(attrs map[string]any) (*ua.DataChangeFilter, error) {
+	typeAttr, hasType := attrs[DEADBANDTYPE]
+	valueAttr, hasValue := attrs[DEADBANDVALUE]
+	triggerAttr, hasTrigger := attrs[DATACHANGETRIGGER]
+	if !hasType && !hasValue && !hasTrigger {
+		return nil, nil
+	}
+
+	filter := &ua.DataChangeFilter{
+		Trigger:      ua.DataChangeTriggerStatusValue,
+		DeadbandType: uint32(ua.DeadbandTypeNone),
+	}
+
+	if hasTrigger {
+		trigger, ok := lookupFold(dataChangeTriggers, cast.ToString(triggerAttr))
+		if !ok {
+			return nil, fmt.Errorf("invalid %s %v: must be Status, StatusValue or StatusValueTimestamp", DATACHANGETRIGGER, triggerAttr)
+		}
+		filter.Trigger = trigger
+	}
+
+	if hasType != hasValue {
+		return nil, fmt.Errorf("%s and %s must be set together", DEADBANDTYPE, DEADBANDVALUE)
+	}
+	if !hasType {
+		return filter, nil
+	}
+
+	deadbandType, ok := lookupFold(deadbandTypes, cast.ToString(typeAttr))
+	if !ok {
+		return nil, fmt.Errorf("invalid %s %v: must be absolute or percent", DEADBANDTYPE, typeAttr)
+	}
+	value, err := cast.ToFloat64E(valueAttr)
+	if err != nil || value < 0 || (deadbandType == ua.DeadbandTypePercent && value > 100) {
+		return nil, fmt.Errorf("invalid %s %v: must be a positive number, at most 100 for a percent deadband", DEADBANDVALUE, valueAttr)
+	}
+	if filter.Trigger == ua.DataChangeTriggerStatus {
+		return nil, fmt.Errorf("a deadband requires the %s to include the value", DATACHANGETRIGGER)
+	}
+
+	filter.DeadbandType = uint32(deadbandType)
+	filter.DeadbandValue = value
+	return filter, nil
+}
+
+// lookupFold finds the value of an attribute whose names are matched case-insensitively
+func lookupFold[T any](values map[string]T, name string) (T, bool) {
+	for key, value := range values {
+		if strings.EqualFold(key, name) {
+			return value, true
+		}
+	}
+	var zero T
+	return zero, false
+}
+
+// apply sets the sampling settings on a monitored item create request
+func (p *monitoringParameters) apply(req *ua.MonitoredItemCreateRequest) {
+	req.RequestedParameters.SamplingInterval = p.samplingInterval
+	req.RequestedParameters.QueueSize = p.queueSize
+	req.RequestedParameters.DiscardOldest = p.discardOldest
+	req.ItemToMonitor.IndexRange = p.indexRange
+	if p.filter != nil {
+		req.RequestedParameters.Filter = ua.NewExtensionObject(p.filter)
+	}
+	if _, ok := p.filter.(*ua.EventFilter); ok {
+		req.ItemToMonitor.AttributeID = ua.AttributeIDEventNotifier
+	}
+}
+
+// isFilterError checks whether a monitored item was rejected because of its filter
+func isFilterError(status ua.StatusCode) bool {
+	return status == ua.StatusBadMonitoredItemFilterInvalid ||
+		status == ua.StatusBadMonitoredItemFilterUnsupported ||
+		status == ua.StatusBadFilterNotAllowed ||
+		status == ua.StatusBadDeadbandFilterInvalid ||
+		status == ua.StatusBadEventFilterInvalid
+}
+
+// isSubscribed checks whether a device resource is monitored by every device of its profile
+func isSubscribed(attrs map[string]any) (bool, error) {
+	v, ok := attrs[SUBSCRIBE]
+	if !ok {
+		return false, nil
+	}
+	subscribe, err := cast.ToBoolE(v)
+	if err != nil {
+		return false, fmt.Errorf("invalid %s %v: %v", SUBSCRIBE, v, err)
+	}
+	return subscribe, nil
+}
+
+// subscribedResources returns the resources to monitor: the device Resources when
+// listed, otherwise the resources of the device profile marked with the subscribe attribute
+func (s *Server) subscribedResources(resources []string) ([]string, error) {
+	if len(resources) > 0 {
+		return resources, nil
+	}
+
+	device, err := s.sdk.GetDeviceByName(s.deviceName)
+	if err != nil {
+		return nil, err
+	}
+	profile, err := s.sdk.GetProfileByName(device.ProfileName)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, resource := range profile.DeviceResources {
+		subscribe, err := isSubscribed(resource.Attributes)
+		if err != nil {
+			return nil, fmt.Errorf("device resource %s: %v", resource.Name, err)
+		}
+		if subscribe {
+			resources = append(resources, resource.Name)
+		}
+	}
+	return resources, nil
+}
+
+// ValidateResource checks the driver specific attributes of a device resource
+func ValidateResource(resource models.DeviceResource) error {
+	if _, err := isSubscribed(resource.Attributes); err != nil {
+		return fmt.Errorf("device resource %s: %v", resource.Name, err)
+	}
+	if err := validateTimestampPolicy(resource.Attributes); err != nil {
+		return fmt.Errorf("device resource %s: %v", resource.Name, err)
+	}
+	if _, err := newMonitoringParameters(resource.Attributes); err != nil {
+		return fmt.Errorf("device resource %s: %v", resource.Name, err)
+	}
+	if isEventResource(resource.Attributes) && resource.Properties.ValueType != common.ValueTypeObject {
+		return fmt.Errorf("device resource %s: events are published as %s readings", resource.Name, common.ValueTypeObject)
+	}
+	return nil
+}
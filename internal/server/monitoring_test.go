@@ -0,0 +1,244 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2022 Schneider Electric
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/edgexfoundry/device-opcua-go/internal/test"
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/models"
+	"github.com/gopcua/opcua"
+	"github.com/gopcua/opcua/ua"
+)
+
+func Test_newMonitoringParameters(t *testing.T) {
+	tests := []struct {
+		name    string
+		attrs   map[string]any
+		want    *monitoringParameters
+		wantErr bool
+	}{
+		{
+			name:  "OK - defaults",
+			attrs: map[string]any{NODE: "ns=2;s=A"},
+			want:  &monitoringParameters{queueSize: defaultQueueSize, discardOldest: defaultDiscardOldest},
+		},
+		{
+			name:  "OK - numbers",
+			attrs: map[string]any{SAMPLINGINTERVAL: 250.5, QUEUESIZE: 100, DISCARDOLDEST: false},
+			want:  &monitoringParameters{samplingInterval: 250.5, queueSize: 100},
+		},
+		{
+			name:  "OK - strings",
+			attrs: map[string]any{SAMPLINGINTERVAL: "-1", QUEUESIZE: "1", DISCARDOLDEST: "true"},
+			want:  &monitoringParameters{samplingInterval: -1, queueSize: 1, discardOldest: true},
+		},
+		{
+			name:    "NOK - negative sampling interval",
+			attrs:   map[string]any{SAMPLINGINTERVAL: -5},
+			wantErr: true,
+		},
+		{
+			name:    "NOK - sampling interval not a number",
+			attrs:   map[string]any{SAMPLINGINTERVAL: "fast"},
+			wantErr: true,
+		},
+		{
+			name:    "NOK - negative queue size",
+			attrs:   map[string]any{QUEUESIZE: -1},
+			wantErr: true,
+		},
+		{
+			name:    "NOK - fractional queue size",
+			attrs:   map[string]any{QUEUESIZE: 1.5},
+			wantErr: true,
+		},
+		{
+			name:  "OK - absolute deadband",
+			attrs: map[string]any{DEADBANDTYPE: "absolute", DEADBANDVALUE: 0.5},
+			want: &monitoringParameters{queueSize: defaultQueueSize, discardOldest: defaultDiscardOldest, filter: &ua.DataChangeFilter{
+				Trigger: ua.DataChangeTriggerStatusValue, DeadbandType: uint32(ua.DeadbandTypeAbsolute), DeadbandValue: 0.5}},
+		},
+		{
+			name:  "OK - percent deadband with timestamp trigger",
+			attrs: map[string]any{DEADBANDTYPE: "Percent", DEADBANDVALUE: "10", DATACHANGETRIGGER: "StatusValueTimestamp"},
+			want: &monitoringParameters{queueSize: defaultQueueSize, discardOldest: defaultDiscardOldest, filter: &ua.DataChangeFilter{
+				Trigger: ua.DataChangeTriggerStatusValueTimestamp, DeadbandType: uint32(ua.DeadbandTypePercent), DeadbandValue: 10}},
+		},
+		{
+			name:  "OK - trigger only",
+			attrs: map[string]any{DATACHANGETRIGGER: "Status"},
+			want: &monitoringParameters{queueSize: defaultQueueSize, discardOldest: defaultDiscardOldest, filter: &ua.DataChangeFilter{
+				Trigger: ua.DataChangeTriggerStatus, DeadbandType: uint32(ua.DeadbandTypeNone)}},
+		},
+		{
+			name:  "OK - trigger in any case",
+			attrs: map[string]any{DATACHANGETRIGGER: "statusvalue"},
+			want: &monitoringParameters{queueSize: defaultQueueSize, discardOldest: defaultDiscardOldest, filter: &ua.DataChangeFilter{
+				Trigger: ua.DataChangeTriggerStatusValue, DeadbandType: uint32(ua.DeadbandTypeNone)}},
+		},
+		{
+			name:    "NOK - deadband type without value",
+			attrs:   map[string]any{DEADBANDTYPE: "absolute"},
+			wantErr: true,
+		},
+		{
+			name:    "NOK - unknown deadband type",
+			attrs:   map[string]any{DEADBANDTYPE: "relative", DEADBANDVALUE: 1},
+			wantErr: true,
+		},
+		{
+			name:    "NOK - percent deadband above 100",
+			attrs:   map[string]any{DEADBANDTYPE: "percent", DEADBANDVALUE: 150},
+			wantErr: true,
+		},
+		{
+			name:    "NOK - negative deadband",
+			attrs:   map[string]any{DEADBANDTYPE: "absolute", DEADBANDVALUE: -1},
+			wantErr: true,
+		},
+		{
+			name:    "NOK - unknown trigger",
+			attrs:   map[string]any{DATACHANGETRIGGER: "Value"},
+			wantErr: true,
+		},
+		{
+			name:    "NOK - deadband with status trigger",
+			attrs:   map[string]any{DEADBANDTYPE: "absolute", DEADBANDVALUE: 1, DATACHANGETRIGGER: "Status"},
+			wantErr: true,
+		},
+		{
+			name:    "NOK - invalid discard policy",
+			attrs:   map[string]any{DISCARDOLDEST: "newest"},
+			wantErr: true,
+		},
+		{
+			name:  "OK - index range",
+			attrs: map[string]any{INDEXRANGE: "0:9"},
+			want:  &monitoringParameters{queueSize: defaultQueueSize, discardOldest: defaultDiscardOldest, indexRange: "0:9"},
+		},
+		{
+			name:    "NOK - invalid index range",
+			attrs:   map[string]any{INDEXRANGE: "9:0"},
+			wantErr: true,
+		},
+		{
+			name:    "NOK - index range on an event resource",
+			attrs:   map[string]any{EVENTFIELDS: []any{"Message"}, INDEXRANGE: "0"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := newMonitoringParameters(tt.attrs)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("newMonitoringParameters() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("newMonitoringParameters() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_monitoringParameters_apply(t *testing.T) {
+	req := opcua.NewMonitoredItemCreateRequestWithDefaults(ua.NewNumericNodeID(2, 1), ua.AttributeIDValue, 42)
+	params := &monitoringParameters{samplingInterval: 1000, queueSize: 1, discardOldest: false, indexRange: "1:2"}
+	params.apply(req)
+
+	got := req.RequestedParameters
+	if got.SamplingInterval != 1000 || got.QueueSize != 1 || got.DiscardOldest || got.ClientHandle != 42 || got.Filter != nil {
+		t.Errorf("unexpected monitoring parameters %+v", got)
+	}
+	if req.ItemToMonitor.IndexRange != "1:2" {
+		t.Errorf("expected index range 1:2, got %q", req.ItemToMonitor.IndexRange)
+	}
+
+	params.filter = &ua.DataChangeFilter{Trigger: ua.DataChangeTriggerStatusValue, DeadbandType: uint32(ua.DeadbandTypeAbsolute), DeadbandValue: 1}
+	params.apply(req)
+	if filter, ok := req.RequestedParameters.Filter.Value.(*ua.DataChangeFilter); !ok || filter != params.filter {
+		t.Errorf("expected data change filter, got %+v", req.RequestedParameters.Filter)
+	}
+}
+
+func Test_isFilterError(t *testing.T) {
+	if !isFilterError(ua.StatusBadDeadbandFilterInvalid) {
+		t.Error("expected deadband filter error")
+	}
+	if isFilterError(ua.StatusBadNodeIDUnknown) {
+		t.Error("expected unknown node not to be a filter error")
+	}
+}
+
+func TestValidateResource(t *testing.T) {
+	if err := ValidateResource(models.DeviceResource{Name: "A", Attributes: map[string]any{QUEUESIZE: 5}}); err != nil {
+		t.Errorf("expected no error, got = %v", err)
+	}
+	if err := ValidateResource(models.DeviceResource{Name: "A", Attributes: map[string]any{QUEUESIZE: "many"}}); err == nil {
+		t.Error("expected invalid queue size error")
+	}
+	if err := ValidateResource(models.DeviceResource{Name: "A", Attributes: map[string]any{SUBSCRIBE: "maybe"}}); err == nil {
+		t.Error("expected invalid subscribe error")
+	}
+}
+
+func TestServer_subscribedResources(t *testing.T) {
+	profile := models.DeviceProfile{
+		Name: "Profile",
+		DeviceResources: []models.DeviceResource{
+			{Name: "A", Attributes: map[string]any{NODE: "ns=2;s=A", SUBSCRIBE: true}},
+			{Name: "B", Attributes: map[string]any{NODE: "ns=2;s=B"}},
+			{Name: "C", Attributes: map[string]any{NODE: "ns=2;s=C", SUBSCRIBE: "true", SAMPLINGINTERVAL: 100}},
+			{Name: "D", Attributes: map[string]any{NODE: "ns=2;s=D", SUBSCRIBE: false}},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		resources []string
+		profile   models.DeviceProfile
+		want      []string
+		wantErr   bool
+	}{
+		{
+			name:      "device resources override the profile",
+			resources: []string{"B"},
+			want:      []string{"B"},
+		},
+		{
+			name:    "profile defaults",
+			profile: profile,
+			want:    []string{"A", "C"},
+		},
+		{
+			name: "invalid subscribe attribute",
+			profile: models.DeviceProfile{Name: "Profile", DeviceResources: []models.DeviceResource{
+				{Name: "A", Attributes: map[string]any{SUBSCRIBE: "maybe"}},
+			}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dsMock := test.NewDSMock(t)
+			if len(tt.resources) == 0 {
+				dsMock.On("GetDeviceByName", "Test").Return(models.Device{Name: "Test", ProfileName: "Profile"}, nil)
+				dsMock.On("GetProfileByName", "Profile").Return(tt.profile, nil)
+			}
+			s := NewServer("Test", dsMock, nil)
+			got, err := s.subscribedResources(tt.resources)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Server.subscribedResources() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Server.subscribedResources() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,169 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2022 Schneider Electric
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"sync"
+	"time"
+
+	"github.com/edgexfoundry/device-opcua-go/pkg/result"
+	sdkModels "github.com/edgexfoundry/device-sdk-go/v3/pkg/models"
+	"github.com/gopcua/opcua/ua"
+	"github.com/spf13/cast"
+)
+
+// Quality policies, applied to the values whose StatusCode is not Good
+const (
+	qualityDrop         = "Drop"
+	qualityPublish      = "Publish"
+	qualityKeepLastGood = "KeepLastGood"
+)
+
+// Tags added to every reading built from an OPC UA data value
+const (
+	statusCodeTag      = "statusCode"
+	severityTag        = "severity"
+	sourceTimestampTag = "sourceTimestamp"
+	serverTimestampTag = "serverTimestamp"
+	nodeIDTag          = "nodeId"
+)
+
+// Severities of a StatusCode, given by its two most significant bits
+const (
+	severityGood      = "Good"
+	severityUncertain = "Uncertain"
+	severityBad       = "Bad"
+)
+
+func statusSeverity(code ua.StatusCode) string {
+	switch {
+	case code&0x80000000 != 0:
+		return severityBad
+	case code&0x40000000 != 0:
+		return severityUncertain
+	default:
+		return severityGood
+	}
+}
+
+// qualityTags describes the quality and origin of a data value
+func qualityTags(attrs map[string]any, dv *ua.DataValue) map[string]string {
+	tags := map[string]string{
+		statusCodeTag: statusCodeName(dv.Status),
+		severityTag:   statusSeverity(dv.Status),
+		nodeIDTag:     cast.ToString(attrs[NODE]),
+	}
+	if !dv.SourceTimestamp.IsZero() {
+		tags[sourceTimestampTag] = dv.SourceTimestamp.UTC().Format(time.RFC3339Nano)
+	}
+	if !dv.ServerTimestamp.IsZero() {
+		tags[serverTimestampTag] = dv.ServerTimestamp.UTC().Format(time.RFC3339Nano)
+	}
+	return tags
+}
+
+// lastGoodValues remembers the last good value of each resource for the KeepLastGood policy
+type lastGoodValues struct {
+	mu     sync.Mutex
+	values map[string]any
+}
+
+func newLastGoodValues() *lastGoodValues {
+	return &lastGoodValues{values: make(map[string]any)}
+}
+
+func (l *lastGoodValues) get(resource string) (any, bool) {
+	if l == nil {
+		return nil, false
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	v, ok := l.values[resource]
+	return v, ok
+}
+
+func (l *lastGoodValues) set(resource string, v any) {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.values[resource] = v
+}
+
+// readingPolicy holds the device policies applied when converting data values into readings
+type readingPolicy struct {
+	timestamps string
+	quality    string
+	lastGood   *lastGoodValues
+}
+
+// readingPolicy returns the policies of the device configuration
+func (s *Server) readingPolicy() readingPolicy {
+	policy := readingPolicy{lastGood: s.lastGood}
+	if s.config != nil {
+		policy.timestamps = s.config.TimestampSource
+		policy.quality = s.config.QualityPolicy
+	}
+	return policy
+}
+
+// readPolicy returns the policies applied to read commands, which drop the values
+// that are not Good unless the device sets a QualityPolicy
+func (s *Server) readPolicy() readingPolicy {
+	policy := s.readingPolicy()
+	if policy.quality == "" {
+		policy.quality = qualityDrop
+	}
+	return policy
+}
+
+// value selects the value to publish for a data value, false when it is dropped.
+// A value that is not Good is dropped, published as is, or replaced by the last
+// good value of the resource, according to the quality policy.
+func (p readingPolicy) value(resource string, dv *ua.DataValue) (any, bool) {
+	var data any
+	if dv.Value != nil {
+		data = dv.Value.Value()
+	}
+	if statusSeverity(dv.Status) == severityGood {
+		return data, data != nil
+	}
+
+	switch p.quality {
+	case qualityDrop:
+		return nil, false
+	case qualityKeepLastGood:
+		return p.lastGood.get(resource)
+	default:
+		return data, data != nil
+	}
+}
+
+// newReading converts a data value into a reading tagged with its quality, or returns
+// nil when the quality policy drops it
+func (p readingPolicy) newReading(req sdkModels.CommandRequest, dv *ua.DataValue) (*sdkModels.CommandValue, error) {
+	data, ok := p.value(req.DeviceResourceName, dv)
+	if !ok {
+		return nil, nil
+	}
+	if _, ok := req.Attributes[INDEXRANGE]; ok {
+		data = rangeElement(req.Type, data)
+	}
+
+	reading, err := result.NewResult(req, data)
+	if err != nil {
+		return reading, err
+	}
+	if statusSeverity(dv.Status) == severityGood {
+		p.lastGood.set(req.DeviceResourceName, data)
+	}
+
+	reading.Origin = readingOrigin(timestampPolicy(req.Attributes, p.timestamps), dv)
+	reading.Tags = qualityTags(req.Attributes, dv)
+	return reading, nil
+}
@@ -0,0 +1,123 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2022 Schneider Electric
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"testing"
+	"time"
+
+	sdkModels "github.com/edgexfoundry/device-sdk-go/v3/pkg/models"
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/common"
+	"github.com/gopcua/opcua/ua"
+)
+
+func Test_statusSeverity(t *testing.T) {
+	tests := []struct {
+		code ua.StatusCode
+		want string
+	}{
+		{code: ua.StatusOK, want: severityGood},
+		{code: ua.StatusUncertainLastUsableValue, want: severityUncertain},
+		{code: ua.StatusBadCommunicationError, want: severityBad},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := statusSeverity(tt.code); got != tt.want {
+				t.Errorf("statusSeverity() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_qualityTags(t *testing.T) {
+	source := time.Date(2022, 1, 2, 3, 4, 5, 0, time.UTC)
+	dv := &ua.DataValue{Status: ua.StatusUncertainLastUsableValue, SourceTimestamp: source}
+
+	tags := qualityTags(map[string]any{NODE: "ns=2;s=edgex/int32/var0"}, dv)
+	want := map[string]string{
+		statusCodeTag:      "UncertainLastUsableValue",
+		severityTag:        severityUncertain,
+		sourceTimestampTag: "2022-01-02T03:04:05Z",
+		nodeIDTag:          "ns=2;s=edgex/int32/var0",
+	}
+	if len(tags) != len(want) {
+		t.Fatalf("qualityTags() = %v, want %v", tags, want)
+	}
+	for k, v := range want {
+		if tags[k] != v {
+			t.Errorf("qualityTags()[%s] = %v, want %v", k, tags[k], v)
+		}
+	}
+}
+
+func Test_readingPolicy_newReading(t *testing.T) {
+	req := sdkModels.CommandRequest{
+		DeviceResourceName: "TestVar1",
+		Attributes:         map[string]any{NODE: "ns=2;s=edgex/int32/var0"},
+		Type:               common.ValueTypeInt32,
+	}
+	good := &ua.DataValue{Value: ua.MustVariant(int32(5)), Status: ua.StatusOK}
+	bad := &ua.DataValue{Value: ua.MustVariant(int32(-1)), Status: ua.StatusBadSensorFailure}
+
+	tests := []struct {
+		name    string
+		quality string
+		values  []*ua.DataValue
+		want    any
+	}{
+		{name: "good value", values: []*ua.DataValue{good}, want: int32(5)},
+		{name: "publish bad value", quality: qualityPublish, values: []*ua.DataValue{good, bad}, want: int32(-1)},
+		{name: "drop bad value", quality: qualityDrop, values: []*ua.DataValue{good, bad}, want: nil},
+		{name: "keep last good value", quality: qualityKeepLastGood, values: []*ua.DataValue{good, bad}, want: int32(5)},
+		{name: "no last good value", quality: qualityKeepLastGood, values: []*ua.DataValue{bad}, want: nil},
+		{name: "no value", values: []*ua.DataValue{{Status: ua.StatusOK}}, want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := readingPolicy{quality: tt.quality, lastGood: newLastGoodValues()}
+			var reading *sdkModels.CommandValue
+			for _, dv := range tt.values {
+				var err error
+				if reading, err = policy.newReading(req, dv); err != nil {
+					t.Fatalf("newReading() error = %v", err)
+				}
+			}
+
+			if tt.want == nil {
+				if reading != nil {
+					t.Errorf("newReading() = %v, want nil", reading)
+				}
+				return
+			}
+			if reading == nil {
+				t.Fatal("newReading() = nil")
+			}
+			if reading.Value != tt.want {
+				t.Errorf("newReading() value = %v, want %v", reading.Value, tt.want)
+			}
+			last := tt.values[len(tt.values)-1]
+			if reading.Tags[statusCodeTag] != statusCodeName(last.Status) {
+				t.Errorf("newReading() statusCode tag = %v, want %v", reading.Tags[statusCodeTag], statusCodeName(last.Status))
+			}
+		})
+	}
+}
+
+func TestServer_readPolicy(t *testing.T) {
+	s := &Server{config: &Config{}}
+	if got := s.readPolicy().quality; got != qualityDrop {
+		t.Errorf("readPolicy() quality = %v, want %v", got, qualityDrop)
+	}
+	if got := s.readingPolicy().quality; got != "" {
+		t.Errorf("readingPolicy() quality = %v, want the Publish default", got)
+	}
+
+	s.config.QualityPolicy = qualityKeepLastGood
+	if got := s.readPolicy().quality; got != qualityKeepLastGood {
+		t.Errorf("readPolicy() quality = %v, want %v", got, qualityKeepLastGood)
+	}
+}
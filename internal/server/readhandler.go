@@ -9,46 +9,82 @@
 package server
 
 import (
+	"errors"
 	"fmt"
+	"strings"
 
-	"github.com/edgexfoundry/device-opcua-go/pkg/result"
 	sdkModel "github.com/edgexfoundry/device-sdk-go/v3/pkg/models"
-	"github.com/edgexfoundry/go-mod-core-contracts/v3/clients/logger"
-	"github.com/gopcua/opcua"
 	"github.com/gopcua/opcua/ua"
 )
 
 type ResultToRequest map[int][]int
 
-func createResult(req sdkModel.CommandRequest, variant *ua.Variant, logger logger.LoggingClient) (response *sdkModel.CommandValue) {
-	var err error
-	if response, err = result.NewResult(req, variant.Value()); err != nil {
-		logger.Errorf("Driver.handleReadCommands: Error: %v", err)
+// ReadFailure values: a read command with failed resources either fails as a whole
+// or returns the readings of the other resources
+const (
+	readFailCommand = "Fail"
+	readPartial     = "Partial"
+)
+
+// readErrorsTag holds the error summary on the readings of a partial read command
+const readErrorsTag = "readErrors"
+
+// readFailure tells why no reading was returned for a resource
+type readFailure struct {
+	resource string
+	reason   string
+}
+
+// readError summarizes the resources of a read command that returned no reading
+type readError struct {
+	failures []readFailure
+	total    int
+}
+
+func (e *readError) Error() string {
+	reasons := make([]string, len(e.failures))
+	for i, f := range e.failures {
+		reasons[i] = fmt.Sprintf("%s: %s", f.resource, f.reason)
+	}
+	return fmt.Sprintf("%d of %d resources failed: %s", len(e.failures), e.total, strings.Join(reasons, "; "))
+}
+
+// failureReason explains why a data value produced no reading
+func failureReason(dv *ua.DataValue, err error) string {
+	switch {
+	case err != nil:
+		return err.Error()
+	case dv.Status != ua.StatusOK:
+		return statusCodeName(dv.Status)
+	default:
+		return "no value"
 	}
-	return response
 }
 
-func (rr ResultToRequest) buildCommandValues(reqs []sdkModel.CommandRequest, resp *ua.ReadResponse, logger logger.LoggingClient) []*sdkModel.CommandValue {
+// buildCommandValues converts the read results into readings according to the
+// timestamp and quality policies. The resources left without a reading are
+// reported in a *readError along with the readings of the others.
+func (rr ResultToRequest) buildCommandValues(reqs []sdkModel.CommandRequest, resp *ua.ReadResponse, policy readingPolicy) ([]*sdkModel.CommandValue, error) {
 	responses := make([]*sdkModel.CommandValue, len(reqs))
+	failed := &readError{total: len(reqs)}
 	for i := 0; i < len(resp.Results); i++ {
-		if resp.Results[i].Status != ua.StatusOK {
-			logger.Debugf("Driver.handleReadCommands: Status not OK: %v", resp.Results[i].Status)
-			continue
-		}
-
-		variant := resp.Results[i].Value
-		if variant == nil || variant.Value() == nil {
-			continue
-		}
-
-		if reqIndexes, ok := rr[i]; ok {
-			for _, reqIndex := range reqIndexes {
-				responses[reqIndex] = createResult(reqs[reqIndex], variant, logger)
+		for _, reqIndex := range rr[i] {
+			reading, err := policy.newReading(reqs[reqIndex], resp.Results[i])
+			if reading == nil || err != nil {
+				failed.failures = append(failed.failures, readFailure{
+					resource: reqs[reqIndex].DeviceResourceName,
+					reason:   failureReason(resp.Results[i], err),
+				})
+				continue
 			}
+			responses[reqIndex] = reading
 		}
 	}
 
-	return responses
+	if len(failed.failures) > 0 {
+		return responses, failed
+	}
+	return responses, nil
 }
 
 func buildNodesToReadRequest(reqs []sdkModel.CommandRequest) (nodesToRead []*ua.ReadValueID, resultToRequest ResultToRequest, err error) {
@@ -66,12 +102,19 @@ func buildNodesToReadRequest(reqs []sdkModel.CommandRequest) (nodesToRead []*ua.
 			return nil, nil, fmt.Errorf("Driver.handleReadCommands: Invalid node id = %v", err)
 		}
 
-		if resultIndex, ok := nodesIdToResultIndex[id.String()]; ok {
+		r, _, err := indexRange(req.Attributes)
+		if err != nil {
+			return nil, nil, fmt.Errorf("Driver.handleReadCommands: %v", err)
+		}
+
+		// resources reading different ranges of a node are read separately
+		key := id.String() + "[" + r + "]"
+		if resultIndex, ok := nodesIdToResultIndex[key]; ok {
 			resultToRequest[resultIndex] = append(resultToRequest[resultIndex], reqIndex)
 		} else {
-			nodesToRead = append(nodesToRead, &ua.ReadValueID{NodeID: id})
+			nodesToRead = append(nodesToRead, &ua.ReadValueID{NodeID: id, IndexRange: r})
 			resultIndex = len(nodesToRead) - 1
-			nodesIdToResultIndex[id.String()] = resultIndex
+			nodesIdToResultIndex[key] = resultIndex
 			resultToRequest[resultIndex] = []int{reqIndex}
 		}
 	}
@@ -95,21 +138,50 @@ func (s *Server) ProcessReadCommands(reqs []sdkModel.CommandRequest) (responses
 	}
 
 	if len(request.NodesToRead) > 0 {
-		if s.client == nil || s.client.State() == opcua.Closed || s.client.State() == opcua.Disconnected {
-			if err := s.Connect(); err != nil {
-				s.sdk.LoggingClient().Errorf("Driver.handleReadCommands: client not initialized: %v", err)
-				return responses, err
-			}
+		client, err := s.connectedClient()
+		if err != nil {
+			s.sdk.LoggingClient().Errorf("Driver.handleReadCommands: client not initialized: %v", err)
+			return responses, err
 		}
 
-		resp, err := s.client.Read(s.client.ctx, request)
+		ctx, cancel := client.requestContext()
+		defer cancel()
+		resp, err := client.Read(ctx, request)
 		if err != nil {
 			s.sdk.LoggingClient().Errorf("Driver.HandleReadCommands: Handle read commands failed: %v", err)
 			return responses, err
 		}
 
-		responses = resultToRequest.buildCommandValues(reqs, resp, s.sdk.LoggingClient())
+		responses, err = resultToRequest.buildCommandValues(reqs, resp, s.readPolicy())
+		if err != nil {
+			return s.readFailed(responses, err)
+		}
 	}
 
 	return responses, nil
 }
+
+// readFailed applies the ReadFailure setting of the device to a read command whose
+// resources did not all return a reading. Partial results are only returned when
+// at least one resource was read, each carrying the error summary in its tags.
+func (s *Server) readFailed(responses []*sdkModel.CommandValue, err error) ([]*sdkModel.CommandValue, error) {
+	var failed *readError
+	partial := s.config == nil || s.config.ReadFailure != readFailCommand
+	if errors.As(err, &failed) && partial && len(failed.failures) < failed.total {
+		s.sdk.LoggingClient().Warnf("[%s] Driver.HandleReadCommands: returning partial results, %v", s.deviceName, err)
+		for _, response := range responses {
+			if response == nil {
+				continue
+			}
+			if response.Tags == nil {
+				response.Tags = make(map[string]string)
+			}
+			response.Tags[readErrorsTag] = err.Error()
+		}
+		return responses, nil
+	}
+
+	err = fmt.Errorf("[%s] read command failed: %w", s.deviceName, err)
+	s.sdk.LoggingClient().Error(err.Error())
+	return responses, err
+}
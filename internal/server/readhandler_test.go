@@ -8,13 +8,14 @@ package server
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/edgexfoundry/device-opcua-go/internal/test"
 	sdkModel "github.com/edgexfoundry/device-sdk-go/v3/pkg/models"
-	"github.com/edgexfoundry/go-mod-core-contracts/v3/clients/logger"
 	"github.com/edgexfoundry/go-mod-core-contracts/v3/common"
 	"github.com/edgexfoundry/go-mod-core-contracts/v3/models"
 	"github.com/gopcua/opcua"
@@ -52,9 +53,9 @@ func TestDriver_ProcessReadCommands(t *testing.T) {
 			wantErr:     true,
 			endpointErr: true,
 		},
-		// non-existent resource will have a nil response and be ignored by edgex
+		// the read of a single non-existent resource fails
 		{
-			name: "OK - non-existent variable",
+			name: "NOK - non-existent variable",
 			args: args{
 				deviceName: "Test",
 				protocols: map[string]models.ProtocolProperties{
@@ -66,7 +67,7 @@ func TestDriver_ProcessReadCommands(t *testing.T) {
 					Type:               common.ValueTypeInt32,
 				}},
 			},
-			want: make([]*sdkModel.CommandValue, 1),
+			wantErr: true,
 		},
 		{
 			name: "NOK - read command - invalid node id",
@@ -195,22 +196,23 @@ func TestDriver_ProcessReadCommands(t *testing.T) {
 			}
 
 			dsMock := test.NewDSMock(t)
-			s := NewServer(tt.args.deviceName, dsMock)
+			s := NewServer(tt.args.deviceName, dsMock, nil)
 			if tt.nilClient {
 				s.client = nil
 				dsMock.On("GetDeviceByName", tt.args.deviceName).Return(models.Device{Name: tt.args.deviceName, Protocols: tt.args.protocols}, nil)
 			} else {
-				s.client = &Client{client, context.Background()}
+				s.client = &Client{Client: client, ctx: context.Background(), timeout: defaultRequestTimeout}
 			}
 			got, err := s.ProcessReadCommands(tt.args.reqs)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Driver.HandleReadCommands() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
-			// Ignore Origin for DeepEqual
+			// Ignore Origin and Tags for DeepEqual
 			for i := range got {
 				if got[i] != nil {
 					got[i].Origin = 0
+					got[i].Tags = nil
 				}
 			}
 
@@ -282,11 +284,11 @@ func TestDriver_ProcessReadCommandsNoServer(t *testing.T) {
 			defer client.Close(ctx)
 
 			dsMock := test.NewDSMock(t)
-			s := NewServer(tt.args.deviceName, dsMock)
+			s := NewServer(tt.args.deviceName, dsMock, nil)
 			if tt.nilClient {
 				s.client = nil
 			} else {
-				s.client = &Client{client, context.Background()}
+				s.client = &Client{Client: client, ctx: context.Background(), timeout: defaultRequestTimeout}
 			}
 			dsMock.On("GetDeviceByName", tt.args.deviceName).Return(models.Device{}, fmt.Errorf("error"))
 
@@ -295,9 +297,10 @@ func TestDriver_ProcessReadCommandsNoServer(t *testing.T) {
 				t.Errorf("Driver.HandleReadCommands() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
-			// Ignore Origin for DeepEqual
+			// Ignore Origin and Tags for DeepEqual
 			if len(got) > 0 && got[0] != nil {
 				got[0].Origin = 0
+				got[0].Tags = nil
 			}
 			if !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("Driver.HandleReadCommands() = %v, want %v", got, tt.want)
@@ -418,6 +421,35 @@ func TestBuildReadRequest(t *testing.T) {
 	}
 }
 
+func TestBuildReadRequestWithIndexRange(t *testing.T) {
+	reqs := []sdkModel.CommandRequest{
+		{Attributes: map[string]interface{}{NODE: "ns=1;i=1"}},
+		{Attributes: map[string]interface{}{NODE: "ns=1;i=1", INDEXRANGE: "0:4"}},
+		{Attributes: map[string]interface{}{NODE: "ns=1;i=1", INDEXRANGE: "0:4"}},
+		{Attributes: map[string]interface{}{NODE: "ns=1;i=1", INDEXRANGE: "1,0:1"}},
+	}
+	nodesToRead, resultToRequest, err := buildNodesToReadRequest(reqs)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var ranges []string
+	for _, node := range nodesToRead {
+		ranges = append(ranges, node.IndexRange)
+	}
+	if !reflect.DeepEqual(ranges, []string{"", "0:4", "1,0:1"}) {
+		t.Fatalf("Unexpected index ranges: %q", ranges)
+	}
+	if !reflect.DeepEqual(resultToRequest, ResultToRequest{0: {0}, 1: {1, 2}, 2: {3}}) {
+		t.Fatalf("Unexpected result to request: %+v", resultToRequest)
+	}
+
+	reqs = []sdkModel.CommandRequest{{Attributes: map[string]interface{}{NODE: "ns=1;i=1", INDEXRANGE: "a:b"}}}
+	if _, _, err := buildNodesToReadRequest(reqs); err == nil {
+		t.Fatal("Invalid index range; error expected")
+	}
+}
+
 func TestBuildReadRequestOnMethod(t *testing.T) {
 	reqs := []sdkModel.CommandRequest{
 		{
@@ -460,8 +492,6 @@ func TestBuildCommandValues(t *testing.T) {
 		},
 	}
 
-	lc := logger.NewMockClient()
-
 	t.Run("Read on one node", func(t *testing.T) {
 		var resultToRequest ResultToRequest = map[int][]int{0: {0, 1, 2}}
 
@@ -473,7 +503,10 @@ func TestBuildCommandValues(t *testing.T) {
 			},
 		}
 
-		commandValues := resultToRequest.buildCommandValues(reqs, uaResponse, lc)
+		commandValues, err := resultToRequest.buildCommandValues(reqs, uaResponse, readingPolicy{})
+		if err != nil {
+			t.Fatalf("Expected no error; got %v", err)
+		}
 
 		if len(commandValues) != 3 {
 			t.Fatalf("Expected number of command values 3; got %d;", len(commandValues))
@@ -505,6 +538,29 @@ func TestBuildCommandValues(t *testing.T) {
 
 	})
 
+	t.Run("Origin from the source timestamp", func(t *testing.T) {
+		var resultToRequest ResultToRequest = map[int][]int{0: {0}}
+		source := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+
+		uaResponse := &ua.ReadResponse{
+			Results: []*ua.DataValue{
+				{
+					Value:           ua.MustVariant(int32(1)),
+					SourceTimestamp: source,
+				},
+			},
+		}
+
+		commandValues, err := resultToRequest.buildCommandValues(reqs, uaResponse, readingPolicy{timestamps: timestampSource})
+		if err != nil {
+			t.Fatalf("Expected no error; got %v", err)
+		}
+
+		if commandValues[0].Origin != source.UnixNano() {
+			t.Fatalf("Expected origin %d; got %d", source.UnixNano(), commandValues[0].Origin)
+		}
+	})
+
 	t.Run("Read on multiple nodes", func(t *testing.T) {
 		var resultToRequest ResultToRequest = map[int][]int{0: {0}, 1: {1}, 2: {2}}
 
@@ -520,7 +576,10 @@ func TestBuildCommandValues(t *testing.T) {
 			},
 		}
 
-		commandValues := resultToRequest.buildCommandValues(reqs, uaResponse, lc)
+		commandValues, err := resultToRequest.buildCommandValues(reqs, uaResponse, readingPolicy{})
+		if err != nil {
+			t.Fatalf("Expected no error; got %v", err)
+		}
 
 		if len(commandValues) != 3 {
 			t.Fatalf("Expected number of command values 3; got %d;", len(commandValues))
@@ -551,4 +610,74 @@ func TestBuildCommandValues(t *testing.T) {
 		}
 
 	})
+	t.Run("Failed resources", func(t *testing.T) {
+		var resultToRequest ResultToRequest = map[int][]int{0: {0}, 1: {1}, 2: {2}}
+
+		uaResponse := &ua.ReadResponse{
+			Results: []*ua.DataValue{
+				{
+					Value: ua.MustVariant(int32(1)),
+				}, {
+					Status: ua.StatusBadNodeIDUnknown,
+				}, {
+					Value: ua.MustVariant("one"),
+				},
+			},
+		}
+
+		commandValues, err := resultToRequest.buildCommandValues(reqs, uaResponse, readingPolicy{})
+		if commandValues[0] == nil || commandValues[1] != nil || commandValues[2] != nil {
+			t.Fatalf("Expected a reading for Res1 only; got %v", commandValues)
+		}
+
+		var failed *readError
+		if !errors.As(err, &failed) {
+			t.Fatalf("Expected a read error; got %v", err)
+		}
+		if len(failed.failures) != 2 || failed.failures[0].resource != "Res2" || failed.failures[0].reason != statusCodeName(ua.StatusBadNodeIDUnknown) || failed.failures[1].resource != "Res3" {
+			t.Fatalf("Expected Res2 and Res3 to fail; got %v", err)
+		}
+	})
+}
+
+func TestServer_readFailed(t *testing.T) {
+	tests := []struct {
+		name        string
+		readFailure string
+		err         error
+		wantErr     bool
+	}{
+		{
+			name: "OK - partial results",
+			err:  &readError{failures: []readFailure{{resource: "Res2", reason: "BadNodeIDUnknown"}}, total: 2},
+		},
+		{
+			name:        "NOK - fail the command",
+			readFailure: readFailCommand,
+			err:         &readError{failures: []readFailure{{resource: "Res2", reason: "BadNodeIDUnknown"}}, total: 2},
+			wantErr:     true,
+		},
+		{
+			name:    "NOK - every resource failed",
+			err:     &readError{failures: []readFailure{{resource: "Res1", reason: "no value"}, {resource: "Res2", reason: "BadNodeIDUnknown"}}, total: 2},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Server{
+				deviceName: "Test",
+				config:     &Config{ReadFailure: tt.readFailure},
+				sdk:        test.NewDSMock(t),
+			}
+			partial := []*sdkModel.CommandValue{{DeviceResourceName: "Res1"}, nil}
+			got, err := s.readFailed(partial, tt.err)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("readFailed() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got[0].Tags[readErrorsTag] != tt.err.Error() {
+				t.Errorf("readFailed() %s tag = %q, want %q", readErrorsTag, got[0].Tags[readErrorsTag], tt.err.Error())
+			}
+		})
+	}
 }
@@ -0,0 +1,154 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2022 Schneider Electric
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"errors"
+	"math"
+	"math/rand/v2"
+	"time"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/models"
+	"github.com/gopcua/opcua"
+	"github.com/gopcua/opcua/ua"
+)
+
+const stateCheckInterval = time.Second
+
+// maxKeepAliveTimeout bounds the wait for a keep-alive, as the default parameters
+// let a subscription stay silent for about 25 minutes
+const maxKeepAliveTimeout = time.Minute
+
+var (
+	errConnectionLost   = errors.New("connection lost")
+	errSubscriptionLost = errors.New("subscription lost")
+	errNoSubscription   = errors.New("no active subscription")
+)
+
+// ReconnectPolicy holds the limits of the jittered exponential backoff applied
+// between two connection attempts of the subscription listener
+type ReconnectPolicy struct {
+	InitialInterval time.Duration
+	MaxInterval     time.Duration
+	Multiplier      float64
+	// DownAfterFailures is the number of consecutive failed connection
+	// attempts after which the device OperatingState is set to Down
+	DownAfterFailures int
+}
+
+// DefaultReconnectPolicy returns the policy used when the service configuration sets none
+func DefaultReconnectPolicy() ReconnectPolicy {
+	return ReconnectPolicy{
+		InitialInterval:   time.Second,
+		MaxInterval:       time.Minute,
+		Multiplier:        2,
+		DownAfterFailures: 3,
+	}
+}
+
+// delay returns the wait before the given retry, starting at 0. The exponential
+// interval is capped by MaxInterval, then randomized between half and all of it
+// so that devices sharing a server do not reconnect in lockstep.
+func (p ReconnectPolicy) delay(retry int) time.Duration {
+	d := float64(p.InitialInterval) * math.Pow(p.Multiplier, float64(retry))
+	if d > float64(p.MaxInterval) || math.IsInf(d, 0) || math.IsNaN(d) {
+		d = float64(p.MaxInterval)
+	}
+	half := int64(d / 2)
+	if half <= 0 {
+		return time.Duration(d)
+	}
+	return time.Duration(half + rand.Int64N(half+1)) // #nosec G404 -- jitter needs no secure random source
+}
+
+// RunSubscriptionListener keeps the subscription listener running until the device
+// is updated or removed. Whenever the connection fails or the session is lost, it
+// reconnects with backoff and re-creates the subscription and its monitored items.
+func (s *Server) RunSubscriptionListener(policy ReconnectPolicy) {
+	s.mu.Lock()
+	ctx := s.context.ctx
+	s.mu.Unlock()
+
+	failures := 0
+	for retry := 0; ; retry++ {
+		err := s.listen(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		s.disconnect()
+
+		switch {
+		// a session that was established resets the backoff
+		case errors.Is(err, errConnectionLost):
+			retry, failures = 0, 0
+		// a locked device stays disconnected until UpdateDevice restarts the listener
+		case errors.Is(err, ErrDeviceUnavailable):
+			s.sdk.LoggingClient().Infof("[%s] device is locked, subscription listener stopped", s.deviceName)
+			return
+		default:
+			failures++
+			if failures >= policy.DownAfterFailures {
+				s.setOperatingState(models.Down)
+			}
+		}
+		delay := policy.delay(retry)
+		s.sdk.LoggingClient().Warnf("[%s] subscription listener stopped: %v. Reconnecting in %v", s.deviceName, err, delay)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+	}
+}
+
+// setOperatingState reports the session state through the device OperatingState
+func (s *Server) setOperatingState(state models.OperatingState) {
+	s.mu.Lock()
+	current := s.operatingState
+	s.mu.Unlock()
+
+	if current == state {
+		return
+	}
+	if err := s.sdk.UpdateDeviceOperatingState(s.deviceName, state); err != nil {
+		s.sdk.LoggingClient().Warnf("[%s] failed to set operating state to %s: %v", s.deviceName, state, err)
+		return
+	}
+
+	s.mu.Lock()
+	s.operatingState = state
+	s.mu.Unlock()
+	s.sdk.LoggingClient().Infof("[%s] operating state set to %s", s.deviceName, state)
+}
+
+// isSessionLost checks whether the client state no longer allows to receive notifications
+func isSessionLost(state opcua.ConnState) bool {
+	return state == opcua.Closed || state == opcua.Disconnected
+}
+
+// isSessionError checks whether a publish error means the session is gone
+func isSessionError(err error) bool {
+	return errors.Is(err, ua.StatusBadSessionIDInvalid) ||
+		errors.Is(err, ua.StatusBadSessionClosed) ||
+		errors.Is(err, ua.StatusBadSecureChannelClosed) ||
+		errors.Is(err, ua.StatusBadConnectionClosed)
+}
+
+// isSubscriptionError checks whether a publish error means the subscription is gone
+// while the session may still be alive
+func isSubscriptionError(err error) bool {
+	return errors.Is(err, ua.StatusBadSubscriptionIDInvalid) ||
+		errors.Is(err, ua.StatusBadNoSubscription)
+}
+
+// keepAliveTimeout returns how long a subscription may stay silent: the server sends
+// a keep-alive after maxKeepAliveCount publishing intervals without notification,
+// which may take up to a request timeout to arrive. It is capped by maxKeepAliveTimeout.
+func keepAliveTimeout(publishingInterval time.Duration, maxKeepAliveCount uint32, requestTimeout time.Duration) time.Duration {
+	return min(publishingInterval*time.Duration(maxKeepAliveCount)+requestTimeout, maxKeepAliveTimeout)
+}
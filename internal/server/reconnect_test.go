@@ -0,0 +1,179 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2022 Schneider Electric
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/edgexfoundry/device-opcua-go/internal/test"
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/models"
+	"github.com/gopcua/opcua"
+	"github.com/gopcua/opcua/ua"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestReconnectPolicy_delay(t *testing.T) {
+	p := ReconnectPolicy{InitialInterval: time.Second, MaxInterval: 10 * time.Second, Multiplier: 2}
+	tests := []struct {
+		retry int
+		max   time.Duration
+	}{
+		{retry: 0, max: time.Second},
+		{retry: 2, max: 4 * time.Second},
+		{retry: 10, max: 10 * time.Second},
+		{retry: 5000, max: 10 * time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("retry %d", tt.retry), func(t *testing.T) {
+			for i := 0; i < 100; i++ {
+				if d := p.delay(tt.retry); d < tt.max/2 || d > tt.max {
+					t.Fatalf("delay(%d) = %v, want within [%v, %v]", tt.retry, d, tt.max/2, tt.max)
+				}
+			}
+		})
+	}
+}
+
+func TestServer_RunSubscriptionListener(t *testing.T) {
+	policy := ReconnectPolicy{InitialInterval: time.Millisecond, MaxInterval: 5 * time.Millisecond, Multiplier: 2, DownAfterFailures: 2}
+
+	tests := []struct {
+		name     string
+		device   models.Device
+		err      error
+		downErr  error
+		wantDown bool
+	}{
+		{
+			name:     "set down after failed attempts",
+			err:      fmt.Errorf("error"),
+			wantDown: true,
+		},
+		{
+			name:     "set down again after a failed update",
+			err:      fmt.Errorf("error"),
+			downErr:  fmt.Errorf("error"),
+			wantDown: true,
+		},
+		{
+			name:   "locked device stops the listener",
+			device: models.Device{AdminState: models.Locked, OperatingState: models.Up},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dsMock := test.NewDSMock(t)
+			dsMock.On("GetDeviceByName", "Test").Return(tt.device, tt.err)
+			down := make(chan struct{})
+			if tt.downErr != nil {
+				dsMock.On("UpdateDeviceOperatingState", "Test", models.OperatingState(models.Down)).Return(tt.downErr).Once()
+			}
+			if tt.wantDown {
+				dsMock.On("UpdateDeviceOperatingState", "Test", models.OperatingState(models.Down)).Return(nil).Once().
+					Run(func(mock.Arguments) { close(down) })
+			}
+
+			s := NewServer("Test", dsMock, nil)
+			done := make(chan struct{})
+			go func() {
+				s.RunSubscriptionListener(policy)
+				close(done)
+			}()
+
+			// a failing listener runs until cleanup, a locked device stops it
+			wait := done
+			if tt.wantDown {
+				wait = down
+			}
+			select {
+			case <-wait:
+			case <-time.After(time.Second):
+				t.Fatal("subscription listener did not reach the expected state")
+			}
+			s.Cleanup(false)
+
+			select {
+			case <-done:
+			case <-time.After(time.Second):
+				t.Fatal("subscription listener did not stop after cleanup")
+			}
+			dsMock.AssertCalled(t, "GetDeviceByName", "Test")
+		})
+	}
+}
+
+func TestServer_setOperatingState(t *testing.T) {
+	t.Run("OK - unchanged state", func(t *testing.T) {
+		s := NewServer("Test", test.NewDSMock(t), nil)
+		s.operatingState = models.Up
+		s.setOperatingState(models.Up)
+	})
+
+	t.Run("OK - state updated once", func(t *testing.T) {
+		dsMock := test.NewDSMock(t)
+		dsMock.On("UpdateDeviceOperatingState", "Test", models.OperatingState(models.Down)).Return(nil).Once()
+		s := NewServer("Test", dsMock, nil)
+		s.operatingState = models.Up
+		s.setOperatingState(models.Down)
+		s.setOperatingState(models.Down)
+	})
+
+	t.Run("NOK - update failed is retried", func(t *testing.T) {
+		dsMock := test.NewDSMock(t)
+		dsMock.On("UpdateDeviceOperatingState", "Test", models.OperatingState(models.Up)).Return(fmt.Errorf("error")).Twice()
+		s := NewServer("Test", dsMock, nil)
+		s.operatingState = models.Down
+		s.setOperatingState(models.Up)
+		s.setOperatingState(models.Up)
+	})
+}
+
+func Test_isSessionLost(t *testing.T) {
+	for state, want := range map[opcua.ConnState]bool{
+		opcua.Closed:       true,
+		opcua.Disconnected: true,
+		opcua.Connected:    false,
+		opcua.Reconnecting: false,
+	} {
+		if got := isSessionLost(state); got != want {
+			t.Errorf("isSessionLost(%s) = %v, want %v", state, got, want)
+		}
+	}
+}
+
+func Test_isSessionError(t *testing.T) {
+	if !isSessionError(fmt.Errorf("publish failed: %w", ua.StatusBadSessionIDInvalid)) {
+		t.Error("expected invalid session to be a session error")
+	}
+	if isSessionError(ua.StatusBadTimeout) {
+		t.Error("expected timeout not to be a session error")
+	}
+	if isSessionError(ua.StatusBadSubscriptionIDInvalid) {
+		t.Error("expected invalid subscription not to be a session error")
+	}
+}
+
+func Test_isSubscriptionError(t *testing.T) {
+	if !isSubscriptionError(fmt.Errorf("publish failed: %w", ua.StatusBadNoSubscription)) {
+		t.Error("expected no subscription to be a subscription error")
+	}
+	if isSubscriptionError(ua.StatusBadSessionClosed) {
+		t.Error("expected closed session not to be a subscription error")
+	}
+}
+
+func Test_keepAliveTimeout(t *testing.T) {
+	if got := keepAliveTimeout(500*time.Millisecond, 10, 10*time.Second); got != 15*time.Second {
+		t.Errorf("keepAliveTimeout() = %v, want %v", got, 15*time.Second)
+	}
+	// the default parameters are capped
+	if got := keepAliveTimeout(500*time.Millisecond, 3000, 10*time.Second); got != maxKeepAliveTimeout {
+		t.Errorf("keepAliveTimeout() = %v, want %v", got, maxKeepAliveTimeout)
+	}
+}
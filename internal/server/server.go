@@ -8,10 +8,15 @@ package server
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"reflect"
 	"sync"
+	"time"
 
+	"github.com/edgexfoundry/device-opcua-go/internal/pki"
 	"github.com/edgexfoundry/device-sdk-go/v3/pkg/interfaces"
+	sdkModels "github.com/edgexfoundry/device-sdk-go/v3/pkg/models"
 	"github.com/edgexfoundry/go-mod-core-contracts/v3/models"
 	"github.com/gopcua/opcua"
 	"github.com/gopcua/opcua/ua"
@@ -24,71 +29,151 @@ type CancelContext struct {
 
 type Client struct {
 	*opcua.Client
-	ctx context.Context
+	ctx     context.Context
+	timeout time.Duration
+}
+
+// requestContext returns the context bounding a single request with the configured timeout
+func (c *Client) requestContext() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(c.ctx, c.timeout)
 }
 
 type Server struct {
-	deviceName  string
-	resourceMap map[uint32]string
-	context     *CancelContext
-	client      *Client
-	config      *Config
-	sdk         interfaces.DeviceServiceSDK
-	mu          sync.Mutex
+	deviceName     string
+	resourceMap    map[uint32]string
+	context        *CancelContext
+	client         *Client
+	config         *Config
+	subscription   *opcua.Subscription
+	monitoredItems map[string]monitoredItem
+	nextHandle     uint32
+	itemsPerCall   int
+	pendingValues  []*sdkModels.CommandValue
+	lastGood       *lastGoodValues
+	adminState     models.AdminState
+	operatingState models.OperatingState
+	sdk            interfaces.DeviceServiceSDK
+	pki            *pki.Store
+	mu             sync.Mutex
 }
 
-func NewServer(deviceName string, sdk interfaces.DeviceServiceSDK) *Server {
+// ErrDeviceUnavailable is returned when a request targets a locked or down device
+var ErrDeviceUnavailable = errors.New("device is locked or down")
+
+func NewServer(deviceName string, sdk interfaces.DeviceServiceSDK, store *pki.Store) *Server {
 	server := &Server{
-		deviceName:  deviceName,
-		resourceMap: make(map[uint32]string),
-		sdk:         sdk,
+		deviceName:     deviceName,
+		resourceMap:    make(map[uint32]string),
+		monitoredItems: make(map[string]monitoredItem),
+		lastGood:       newLastGoodValues(),
+		sdk:            sdk,
+		pki:            store,
 	}
 	server.newContext()
 	return server
 }
 
 func (s *Server) Connect() error {
+	s.mu.Lock()
+	ctx := s.context.ctx
+	s.mu.Unlock()
+
+	_, err := s.connect(ctx, false)
+	return err
+}
+
+// connect opens a new session, the endpoints being discovered under the given context.
+// The subscription listener ignores the Down operating state, as it is the one restoring it.
+func (s *Server) connect(ctx context.Context, ignoreDown bool) (*Client, error) {
 	device, err := s.sdk.GetDeviceByName(s.deviceName)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	if device.AdminState == models.Locked || device.OperatingState == models.Down {
-		return fmt.Errorf("client not started for [%s]: device is locked or down", s.deviceName)
+	s.mu.Lock()
+	s.adminState = device.AdminState
+	s.operatingState = device.OperatingState
+	s.mu.Unlock()
+
+	if device.AdminState == models.Locked || (!ignoreDown && device.OperatingState == models.Down) {
+		return nil, fmt.Errorf("client not started for [%s]: %w", s.deviceName, ErrDeviceUnavailable)
 	}
 
 	serverConfig, err := NewConfig(device.Protocols["opcua"])
 	if err != nil {
-		return err
+		return nil, err
 	}
 	s.mu.Lock()
 	s.config = serverConfig
 	s.mu.Unlock()
 
-	if err := s.initClient(); err != nil {
-		return err
+	client, err := s.initClient(ctx)
+	if err != nil {
+		return nil, err
 	}
 
-	if err := s.client.Connect(s.client.ctx); err != nil {
+	connectCtx, cancel := context.WithTimeout(client.ctx, serverConfig.connectTimeout())
+	defer cancel()
+	if err := client.Connect(connectCtx); err != nil {
 		s.sdk.LoggingClient().Warnf("[%s] failed to connect OPCUA client: %v", s.deviceName, err)
-		return err
+		return nil, err
 	}
 
-	return nil
+	return client, nil
 }
 
-func (s *Server) Cleanup(recreateContext bool) {
+// connectedClient returns the client of the current session, connecting first when there
+// is none. The client is read under the lock, as the subscription listener replaces it.
+func (s *Server) connectedClient() (*Client, error) {
+	s.mu.Lock()
+	client := s.client
+	ctx := s.context.ctx
+	s.mu.Unlock()
+
+	if client != nil && client.State() != opcua.Closed && client.State() != opcua.Disconnected {
+		return client, nil
+	}
+	return s.connect(ctx, false)
+}
+
+// ProtocolsChanged checks whether the protocol properties differ from the ones
+// the current session was opened with
+func (s *Server) ProtocolsChanged(protocols map[string]models.ProtocolProperties) bool {
+	cfg, err := NewConfig(protocols["opcua"])
+	if err != nil || cfg == nil {
+		return true
+	}
+
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	if s.client != nil {
-		// Connection could have been opened from
-		// subscriptionlistener, readhandler, writehandler, or methodhandler
-		if err := s.client.Close(s.client.ctx); err != nil {
-			s.sdk.LoggingClient().Warnf("[%s] failed to close OPCUA client: %v", s.deviceName, err)
-		}
-		s.client = nil
+	return s.config == nil || !reflect.DeepEqual(cfg, s.config)
+}
+
+// ConnectionChanged checks whether the protocol properties differ from the ones the
+// current session was opened with, apart from the monitored Resources
+func (s *Server) ConnectionChanged(protocols map[string]models.ProtocolProperties) bool {
+	cfg, err := NewConfig(protocols["opcua"])
+	if err != nil || cfg == nil {
+		return true
 	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.config == nil {
+		return true
+	}
+	current := *s.config
+	current.Resources = cfg.Resources
+	return !reflect.DeepEqual(cfg, &current)
+}
+
+func (s *Server) Cleanup(recreateContext bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.closeClient()
 	if s.context != nil {
 		s.context.cancel()
 		s.context = nil
@@ -98,6 +183,28 @@ func (s *Server) Cleanup(recreateContext bool) {
 	}
 }
 
+// disconnect closes the session without cancelling the context of the subscription listener
+func (s *Server) disconnect() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.closeClient()
+}
+
+// closeClient closes the OPCUA client, if any. The caller must hold s.mu
+func (s *Server) closeClient() {
+	if s.client != nil {
+		// Connection could have been opened from
+		// subscriptionlistener, readhandler, writehandler, or methodhandler
+		ctx, cancel := s.client.requestContext()
+		defer cancel()
+		if err := s.client.Close(ctx); err != nil {
+			s.sdk.LoggingClient().Warnf("[%s] failed to close OPCUA client: %v", s.deviceName, err)
+		}
+		s.client = nil
+	}
+}
+
 func (s *Server) newContext() {
 	ctxbg := context.Background()
 	ctx, cancel := context.WithCancel(ctxbg)
@@ -108,41 +215,82 @@ func (s *Server) newContext() {
 	}
 }
 
-func (s *Server) initClient() error {
+// initClient creates the client of a new session, closing the one it replaces
+func (s *Server) initClient(ctx context.Context) (*Client, error) {
 
-	endpoints, err := opcua.GetEndpoints(s.context.ctx, s.config.Endpoint)
+	endpointsCtx, cancel := context.WithTimeout(ctx, s.config.connectTimeout())
+	defer cancel()
+	endpoints, err := opcua.GetEndpoints(endpointsCtx, s.config.Endpoint)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	ep, err := opcua.SelectEndpoint(endpoints, s.config.Policy, ua.MessageSecurityModeFromString(s.config.Mode))
 	if err != nil {
 		s.sdk.LoggingClient().Error(err.Error())
-		return fmt.Errorf("[%s] failed to find suitable endpoint", s.deviceName)
+		return nil, fmt.Errorf("[%s] failed to find suitable endpoint", s.deviceName)
 	}
 	ep.EndpointURL = s.config.Endpoint
 
+	if err := s.verifyServerCertificate(ep); err != nil {
+		return nil, err
+	}
+
+	authOpts, tokenType, err := s.authOptions()
+	if err != nil {
+		return nil, err
+	}
+	if tokenType != ua.UserTokenTypeAnonymous && !supportsUserTokenType(ep, tokenType) {
+		return nil, fmt.Errorf("[%s] endpoint does not support user token type %s", s.deviceName, tokenType)
+	}
+
+	certOpts, err := s.certificateOptions()
+	if err != nil {
+		return nil, err
+	}
+
 	opts := []opcua.Option{
 		opcua.SecurityPolicy(s.config.Policy),
 		opcua.SecurityModeString(s.config.Mode),
-		opcua.CertificateFile(s.config.CertFile),
-		opcua.PrivateKeyFile(s.config.KeyFile),
-		opcua.AuthAnonymous(),
-		opcua.SecurityFromEndpoint(ep, ua.UserTokenTypeAnonymous),
+		// A lost session is restored by RunSubscriptionListener, which also rebuilds the subscription
+		opcua.AutoReconnect(false),
 	}
+	opts = append(opts, s.timeoutOptions()...)
+	opts = append(opts, certOpts...)
+	opts = append(opts, authOpts...)
+	opts = append(opts, opcua.SecurityFromEndpoint(ep, tokenType))
 
 	uaClient, err := opcua.NewClient(ep.EndpointURL, opts...)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	client := &Client{
+		Client:  uaClient,
+		ctx:     context.Background(),
+		timeout: s.config.requestTimeout(),
 	}
 
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	s.client = &Client{
-		uaClient,
-		context.Background(),
-	}
+	s.closeClient()
+	s.client = client
+	return client, nil
+}
 
-	return nil
+// timeoutOptions returns the client options for the configured timeouts,
+// the library defaults applying to the ones left empty
+func (s *Server) timeoutOptions() []opcua.Option {
+	opts := []opcua.Option{
+		opcua.DialTimeout(s.config.connectTimeout()),
+		opcua.RequestTimeout(s.config.requestTimeout()),
+	}
+	if s.config.SessionTimeout > 0 {
+		opts = append(opts, opcua.SessionTimeout(time.Duration(s.config.SessionTimeout)))
+	}
+	if s.config.SecureChannelLifetime > 0 {
+		opts = append(opts, opcua.Lifetime(time.Duration(s.config.SecureChannelLifetime)))
+	}
+	return opts
 }
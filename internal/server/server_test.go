@@ -7,8 +7,10 @@
 package server
 
 import (
+	"errors"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/edgexfoundry/device-opcua-go/internal/test"
 	"github.com/edgexfoundry/device-sdk-go/v3/pkg/interfaces/mocks"
@@ -19,7 +21,7 @@ import (
 func TestNewServer(t *testing.T) {
 	t.Run("create server", func(t *testing.T) {
 
-		s := NewServer("test", mocks.NewDeviceServiceSDK(t))
+		s := NewServer("test", mocks.NewDeviceServiceSDK(t), nil)
 		if s == nil {
 			t.Error("NewServer() failed")
 		}
@@ -58,6 +60,28 @@ func TestServer_Cleanup(t *testing.T) {
 		})
 	}
 }
+func TestServer_connectedClient(t *testing.T) {
+	t.Run("NOK - session closed concurrently", func(t *testing.T) {
+		dsMock := test.NewDSMock(t)
+		dsMock.On("GetDeviceByName", "Test").Return(models.Device{Name: "Test", AdminState: models.Locked}, nil)
+		s := NewServer("Test", dsMock, nil)
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			for i := 0; i < 100; i++ {
+				s.disconnect()
+			}
+		}()
+		for i := 0; i < 100; i++ {
+			if _, err := s.connectedClient(); !errors.Is(err, ErrDeviceUnavailable) {
+				t.Fatalf("expected device unavailable, got = %v", err)
+			}
+		}
+		<-done
+	})
+}
+
 func TestServer_Connect(t *testing.T) {
 	deviceName := "testDevice"
 	mockDevice := models.Device{
@@ -78,7 +102,7 @@ func TestServer_Connect(t *testing.T) {
 		mockSDK := mocks.NewDeviceServiceSDK(t)
 		mockSDK.On("GetDeviceByName", deviceName).Return(mockDevice, nil)
 
-		server := NewServer(deviceName, mockSDK)
+		server := NewServer(deviceName, mockSDK, nil)
 		err := server.Connect()
 		assert.NoError(t, err)
 	})
@@ -88,7 +112,7 @@ func TestServer_Connect(t *testing.T) {
 		mockSDK := mocks.NewDeviceServiceSDK(t)
 		mockSDK.On("GetDeviceByName", deviceName).Return(mockDevice, nil)
 
-		server := NewServer(deviceName, mockSDK)
+		server := NewServer(deviceName, mockSDK, nil)
 		err := server.Connect()
 		assert.Error(t, err)
 		assert.EqualError(t, err, fmt.Sprintf("client not started for [%s]: device is locked or down", deviceName))
@@ -100,7 +124,7 @@ func TestServer_Connect(t *testing.T) {
 		mockSDK := mocks.NewDeviceServiceSDK(t)
 		mockSDK.On("GetDeviceByName", deviceName).Return(mockDevice, nil)
 
-		server := NewServer(deviceName, mockSDK)
+		server := NewServer(deviceName, mockSDK, nil)
 		err := server.Connect()
 		assert.Error(t, err)
 		assert.EqualError(t, err, fmt.Sprintf("client not started for [%s]: device is locked or down", deviceName))
@@ -112,9 +136,104 @@ func TestServer_Connect(t *testing.T) {
 		mockSDK := mocks.NewDeviceServiceSDK(t)
 		mockSDK.On("GetDeviceByName", deviceName).Return(models.Device{}, fmt.Errorf("error getting device"))
 
-		server := NewServer(deviceName, mockSDK)
+		server := NewServer(deviceName, mockSDK, nil)
 		err := server.Connect()
 		assert.Error(t, err)
 		assert.EqualError(t, err, "error getting device")
 	})
 }
+
+func TestServer_ProtocolsChanged(t *testing.T) {
+	protocols := map[string]models.ProtocolProperties{
+		"opcua": {"Endpoint": "opc.tcp://test", "Resources": []string{"a"}},
+	}
+
+	tests := []struct {
+		name   string
+		config *Config
+		want   bool
+	}{
+		{
+			name: "never connected",
+			want: true,
+		},
+		{
+			name:   "unchanged",
+			config: &Config{Endpoint: "opc.tcp://test", Resources: []string{"a"}},
+		},
+		{
+			name:   "protocol properties changed",
+			config: &Config{Endpoint: "opc.tcp://other", Resources: []string{"a"}},
+			want:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewServer("Test", test.NewDSMock(t), nil)
+			s.config = tt.config
+			if got := s.ProtocolsChanged(protocols); got != tt.want {
+				t.Errorf("Server.ProtocolsChanged() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestServer_ConnectionChanged(t *testing.T) {
+	protocols := map[string]models.ProtocolProperties{
+		"opcua": {"Endpoint": "opc.tcp://test", "Resources": []string{"a", "b"}},
+	}
+
+	tests := []struct {
+		name   string
+		config *Config
+		want   bool
+	}{
+		{
+			name: "never connected",
+			want: true,
+		},
+		{
+			name:   "resources changed",
+			config: &Config{Endpoint: "opc.tcp://test", Resources: []string{"a"}},
+		},
+		{
+			name:   "endpoint changed",
+			config: &Config{Endpoint: "opc.tcp://other", Resources: []string{"a", "b"}},
+			want:   true,
+		},
+		{
+			name:   "subscription parameters changed",
+			config: &Config{Endpoint: "opc.tcp://test", Resources: []string{"a", "b"}, PublishingInterval: Duration(time.Second)},
+			want:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewServer("Test", test.NewDSMock(t), nil)
+			s.config = tt.config
+			if got := s.ConnectionChanged(protocols); got != tt.want {
+				t.Errorf("Server.ConnectionChanged() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestServer_timeoutOptions(t *testing.T) {
+	tests := []struct {
+		name   string
+		config *Config
+		want   int
+	}{
+		{name: "defaults", config: &Config{}, want: 2},
+		{name: "session and secure channel", config: &Config{SessionTimeout: Duration(time.Minute), SecureChannelLifetime: Duration(time.Hour)}, want: 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewServer("Test", test.NewDSMock(t), nil)
+			s.config = tt.config
+			if got := len(s.timeoutOptions()); got != tt.want {
+				t.Errorf("Server.timeoutOptions() returned %d options, want %d", got, tt.want)
+			}
+		})
+	}
+}
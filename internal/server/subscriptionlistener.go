@@ -9,92 +9,480 @@
 package server
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/edgexfoundry/device-opcua-go/pkg/result"
 	sdkModels "github.com/edgexfoundry/device-sdk-go/v3/pkg/models"
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/models"
 	"github.com/gopcua/opcua"
+	"github.com/gopcua/opcua/id"
 	"github.com/gopcua/opcua/ua"
 )
 
+// defaultItemsPerCall bounds the monitored items handled by a single request
+// when the server sets no MaxMonitoredItemsPerCall operation limit
+const defaultItemsPerCall = 1000
+
 // StartSubscriptionListener initializes a new OPCUA client and subscribes to the resources
 // specified by the user in the device protocol configuration
 func (s *Server) StartSubscriptionListener() error {
-	if err := s.Connect(); err != nil {
+	s.mu.Lock()
+	ctx := s.context.ctx
+	s.mu.Unlock()
+
+	return s.listen(ctx)
+}
+
+// listen connects to the server and forwards the subscribed data changes until ctx is
+// cancelled or the session is lost
+func (s *Server) listen(ctx context.Context) error {
+	client, err := s.connect(ctx, true)
+	if err != nil {
 		return err
 	}
 	// Connection will be explicitely closed by s.Cleanup, which is called by the Device Service
 	// when the device is removed or updated. Otherwise it will be closed when the service stops
 
+	recreated := false
+	for {
+		delivered, err := s.subscribe(ctx, client)
+		if !errors.Is(err, errSubscriptionLost) {
+			return err
+		}
+		// the session may outlive its subscription, which is re-created once before
+		// escalating to a full reconnect, unless the re-created one delivered meanwhile
+		if delivered {
+			recreated = false
+		}
+		if state := client.State(); recreated || isSessionLost(state) {
+			return fmt.Errorf("%w: %v", errConnectionLost, err)
+		}
+		s.sdk.LoggingClient().Warnf("%v, re-creating the subscription", err)
+		recreated = true
+	}
+}
+
+// subscribe creates the subscription and its monitored items, then forwards the
+// notifications until ctx is cancelled or the subscription or session is lost. It
+// reports whether the subscription delivered a notification or passed a keep-alive check.
+func (s *Server) subscribe(ctx context.Context, client *Client) (bool, error) {
+	// UpdateResources replaces the configuration while the subscription runs
+	s.mu.Lock()
+	config := s.config
+	s.mu.Unlock()
+
 	notifyCh := make(chan *opcua.PublishNotificationData)
 
-	sub, err := s.client.Subscribe(s.client.ctx,
-		&opcua.SubscriptionParameters{
-			Interval: time.Duration(500) * time.Millisecond,
-		}, notifyCh)
+	subCtx, cancel := client.requestContext()
+	sub, err := client.Subscribe(subCtx, config.subscriptionParameters(), notifyCh)
+	cancel()
 	if err != nil {
-		return err
+		return false, err
 	}
-	defer sub.Cancel(s.client.ctx) //nolint:errcheck
+	// servers may revise the requested parameters
+	s.sdk.LoggingClient().Infof("[%s] subscription %d created: publishing interval %v, lifetime count %d, max keep-alive count %d",
+		s.deviceName, sub.SubscriptionID, sub.RevisedPublishingInterval, sub.RevisedLifetimeCount, sub.RevisedMaxKeepAliveCount)
+	s.mu.Lock()
+	s.subscription = sub
+	s.resourceMap = make(map[uint32]string)
+	s.monitoredItems = make(map[string]monitoredItem)
+	s.itemsPerCall = 0
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		s.subscription = nil
+		s.monitoredItems = nil
+		s.mu.Unlock()
+
+		cancelCtx, cancel := client.requestContext()
+		defer cancel()
+		sub.Cancel(cancelCtx) //nolint:errcheck
+	}()
 
 	if err := s.configureMonitoredItems(sub); err != nil {
-		return err
+		return false, err
+	}
+	s.setOperatingState(models.Up)
+
+	// the client reports a dropped connection through its state only
+	ticker := time.NewTicker(stateCheckInterval)
+	defer ticker.Stop()
+
+	// readings batched per window are sent at each tick, and when the subscription stops
+	var flush <-chan time.Time
+	if window := config.readingBatchWindow(); window > 0 {
+		flushTicker := time.NewTicker(window)
+		defer flushTicker.Stop()
+		flush = flushTicker.C
 	}
+	defer s.flushReadings()
+
+	// the client does not forward keep-alives, so the subscription is probed
+	// whenever no notification arrived within the keep-alive timeout
+	keepAlive := keepAliveTimeout(sub.RevisedPublishingInterval, sub.RevisedMaxKeepAliveCount, client.timeout)
+	watchdog := time.NewTimer(keepAlive)
+	defer watchdog.Stop()
+
+	delivered := false
 
 	// read from subscription's notification channel until ctx is cancelled
 	for {
 		select {
 		// context return
-		case <-s.context.ctx.Done():
-			return nil
+		case <-ctx.Done():
+			return delivered, nil
+		case <-ticker.C:
+			if state := client.State(); isSessionLost(state) {
+				return delivered, fmt.Errorf("[%s] %w: client %s", s.deviceName, errConnectionLost, state)
+			}
+		case <-flush:
+			s.flushReadings()
+		case <-watchdog.C:
+			if err := s.probeSubscription(client, sub.SubscriptionID); err != nil {
+				s.sdk.LoggingClient().Warnf("[%s] no keep-alive received from subscription %d within %v", s.deviceName, sub.SubscriptionID, keepAlive)
+				return delivered, err
+			}
+			delivered = true
+			watchdog.Reset(keepAlive)
 		// receive Publish Notification Data
 		case res := <-notifyCh:
-			if res.Error != nil {
-				s.sdk.LoggingClient().Debug(res.Error.Error())
-				continue
+			if err := s.handleNotification(res, client.State()); err != nil {
+				return delivered, err
+			}
+			if res.Error == nil {
+				delivered = true
 			}
-			switch dataChangeNotification := res.Value.(type) {
-			// result type: DateChange StatusChange
-			case *ua.DataChangeNotification:
-				s.handleDataChange(dataChangeNotification)
+			if !watchdog.Stop() {
+				<-watchdog.C
 			}
+			watchdog.Reset(keepAlive)
+		}
+	}
+}
+
+// handleNotification forwards the data of a publish notification, and returns an error
+// when the notification reports a lost subscription or session
+func (s *Server) handleNotification(res *opcua.PublishNotificationData, state opcua.ConnState) error {
+	if res.Error != nil {
+		switch {
+		case isSessionLost(state) || isSessionError(res.Error):
+			return fmt.Errorf("[%s] %w: %v", s.deviceName, errConnectionLost, res.Error)
+		case isSubscriptionError(res.Error):
+			s.sdk.LoggingClient().Warnf("[%s] subscription %d failed: %v", s.deviceName, res.SubscriptionID, res.Error)
+			return fmt.Errorf("[%s] %w: %v", s.deviceName, errSubscriptionLost, res.Error)
 		}
+		s.sdk.LoggingClient().Debug(res.Error.Error())
+		return nil
 	}
+
+	switch notification := res.Value.(type) {
+	case *ua.DataChangeNotification:
+		s.handleDataChange(notification)
+	case *ua.EventNotificationList:
+		s.handleEvents(notification)
+	// the server no longer publishes for this subscription, e.g. BadTimeout once its lifetime expired
+	case *ua.StatusChangeNotification:
+		status := statusCodeName(notification.Status)
+		s.sdk.LoggingClient().Warnf("[%s] subscription %d status changed to %s", s.deviceName, res.SubscriptionID, status)
+		return fmt.Errorf("[%s] %w: %s", s.deviceName, errSubscriptionLost, status)
+	}
+	return nil
+}
+
+// probeSubscription checks that the session is alive and that the server still holds
+// the subscription, by enabling its publishing again
+func (s *Server) probeSubscription(client *Client, subscriptionID uint32) error {
+	ctx, cancel := client.requestContext()
+	defer cancel()
+
+	req := &ua.SetPublishingModeRequest{
+		PublishingEnabled: true,
+		SubscriptionIDs:   []uint32{subscriptionID},
+	}
+	var res *ua.SetPublishingModeResponse
+	err := client.Send(ctx, req, func(v ua.Response) error {
+		r, ok := v.(*ua.SetPublishingModeResponse)
+		if !ok {
+			return fmt.Errorf("unexpected response %T", v)
+		}
+		res = r
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("[%s] %w: subscription probe failed: %v", s.deviceName, errConnectionLost, err)
+	}
+	if len(res.Results) > 0 && res.Results[0] != ua.StatusOK {
+		return fmt.Errorf("[%s] %w: %s", s.deviceName, errSubscriptionLost, statusCodeName(res.Results[0]))
+	}
+	return nil
+}
+
+// monitoredItem identifies the monitored item of a resource in the running subscription
+type monitoredItem struct {
+	handle uint32
+	id     uint32
 }
 
 func (s *Server) configureMonitoredItems(sub *opcua.Subscription) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	var i uint32
-	for _, resource := range s.config.Resources {
-		deviceResource, ok := s.sdk.DeviceResource(s.deviceName, resource)
-		if !ok {
-			s.sdk.LoggingClient().Warnf("[%s] unable to find resource with name %s", s.deviceName, resource)
-			i++
+	resources, err := s.subscribedResources(s.config.Resources)
+	if err != nil {
+		return fmt.Errorf("[%s] unable to list the subscribed resources: %v", s.deviceName, err)
+	}
+	return s.monitorResources(sub, resources)
+}
+
+// pendingItem is a monitored item create request waiting for the server response
+type pendingItem struct {
+	resource string
+	handle   uint32
+	filter   any
+	request  *ua.MonitoredItemCreateRequest
+}
+
+// newPendingItem builds the create request of the monitored item of a resource
+func (s *Server) newPendingItem(resource string) (*pendingItem, error) {
+	deviceResource, ok := s.sdk.DeviceResource(s.deviceName, resource)
+	if !ok {
+		return nil, fmt.Errorf("unable to find resource with name %s", resource)
+	}
+	// the profile is not validated when it was not cached yet as the device was added
+	if err := ValidateResource(deviceResource); err != nil {
+		return nil, err
+	}
+
+	nodeID, err := getNodeID(deviceResource.Attributes, NODE)
+	if err != nil {
+		return nil, fmt.Errorf("device resource %s: %v", resource, err)
+	}
+	params, err := newMonitoringParameters(deviceResource.Attributes)
+	if err != nil {
+		return nil, fmt.Errorf("device resource %s: %v", resource, err)
+	}
+
+	// arbitrary client handle for the monitoring item
+	handle := s.nextHandle + 42
+	s.nextHandle++
+	request := opcua.NewMonitoredItemCreateRequestWithDefaults(nodeID, ua.AttributeIDValue, handle)
+	params.apply(request)
+	// items added while the device is locked are created disabled
+	if s.adminState == models.Locked {
+		request.MonitoringMode = ua.MonitoringModeDisabled
+	}
+	return &pendingItem{resource: resource, handle: handle, filter: params.filter, request: request}, nil
+}
+
+// monitorResources creates the monitored items of the resources in batches bounded by the
+// operation limit of the server, the caller holding mu. A resource that cannot be monitored
+// is reported and skipped; only a failed request aborts.
+func (s *Server) monitorResources(sub *opcua.Subscription, resources []string) error {
+	var items []*pendingItem
+	for _, resource := range resources {
+		item, err := s.newPendingItem(resource)
+		if err != nil {
+			s.sdk.LoggingClient().Errorf("[%s] %v", s.deviceName, err)
 			continue
 		}
+		items = append(items, item)
+	}
+
+	// the limit is read once per subscription, as the server may have changed
+	if len(items) > 0 && s.itemsPerCall == 0 {
+		s.itemsPerCall = s.readItemsPerCall()
+	}
+
+	monitored := 0
+	for _, batch := range batches(len(items), s.itemsPerCall) {
+		requests := make([]*ua.MonitoredItemCreateRequest, 0, batch[1]-batch[0])
+		for _, item := range items[batch[0]:batch[1]] {
+			requests = append(requests, item.request)
+		}
 
-		id, err := getNodeID(deviceResource.Attributes, NODE)
+		ctx, cancel := s.client.requestContext()
+		res, err := sub.Monitor(ctx, ua.TimestampsToReturnBoth, requests...)
+		cancel()
 		if err != nil {
-			return err
+			return fmt.Errorf("[%s] failed to create monitored items: %v", s.deviceName, err)
+		}
+
+		for i, item := range items[batch[0]:batch[1]] {
+			if i >= len(res.Results) {
+				s.sdk.LoggingClient().Errorf("[%s] no result returned for the monitored item of resource %s", s.deviceName, item.resource)
+				continue
+			}
+			status := res.Results[i].StatusCode
+			switch {
+			case status == ua.StatusOK:
+			case item.filter != nil && isFilterError(status):
+				s.sdk.LoggingClient().Errorf("[%s] server rejected the filter of resource %s: %s", s.deviceName, item.resource, statusCodeName(status))
+				continue
+			default:
+				s.sdk.LoggingClient().Errorf("[%s] server rejected the monitored item of resource %s: %s", s.deviceName, item.resource, statusCodeName(status))
+				continue
+			}
+			// map the client handle so we know what the value returned represents
+			s.resourceMap[item.handle] = item.resource
+			s.monitoredItems[item.resource] = monitoredItem{handle: item.handle, id: res.Results[i].MonitoredItemID}
+			s.sdk.LoggingClient().Debugf("[%s] start incoming data listening for %s", s.deviceName, item.resource)
+			monitored++
 		}
+	}
+
+	s.sdk.LoggingClient().Infof("[%s] %d of %d resources monitored", s.deviceName, monitored, len(resources))
+	return nil
+}
+
+// readItemsPerCall reads the MaxMonitoredItemsPerCall operation limit of the server,
+// bounded by defaultItemsPerCall which also applies when the server sets no limit
+func (s *Server) readItemsPerCall() int {
+	ctx, cancel := s.client.requestContext()
+	defer cancel()
+
+	req := &ua.ReadRequest{
+		NodesToRead: []*ua.ReadValueID{{
+			NodeID:      ua.NewNumericNodeID(0, id.Server_ServerCapabilities_OperationLimits_MaxMonitoredItemsPerCall),
+			AttributeID: ua.AttributeIDValue,
+		}},
+	}
+	res, err := s.client.Read(ctx, req)
+	if err != nil || len(res.Results) == 0 || res.Results[0].Status != ua.StatusOK || res.Results[0].Value == nil {
+		s.sdk.LoggingClient().Debugf("[%s] MaxMonitoredItemsPerCall not available, creating up to %d items per call", s.deviceName, defaultItemsPerCall)
+		return defaultItemsPerCall
+	}
+	limit, _ := res.Results[0].Value.Value().(uint32)
+	return itemsPerCall(limit)
+}
+
+// itemsPerCall returns the batch size allowed by an operation limit, 0 meaning no limit
+func itemsPerCall(limit uint32) int {
+	if limit == 0 || limit > defaultItemsPerCall {
+		return defaultItemsPerCall
+	}
+	return int(limit)
+}
 
-		// arbitrary client handle for the monitoring item
-		handle := i + 42
-		// map the client handle so we know what the value returned represents
-		s.resourceMap[handle] = resource
-		miCreateRequest := opcua.NewMonitoredItemCreateRequestWithDefaults(id, ua.AttributeIDValue, handle)
-		res, err := sub.Monitor(s.client.ctx, ua.TimestampsToReturnBoth, miCreateRequest)
-		if err != nil || res.Results[0].StatusCode != ua.StatusOK {
+// batches splits n items into consecutive [start, end) ranges of at most size items
+func batches(n, size int) [][2]int {
+	if size <= 0 {
+		size = defaultItemsPerCall
+	}
+	var ranges [][2]int
+	for start := 0; start < n; start += size {
+		ranges = append(ranges, [2]int{start, min(start+size, n)})
+	}
+	return ranges
+}
+
+// UpdateResources adds and removes monitored items of the running subscription so that
+// they match the Resources of the protocol properties, keeping the session
+func (s *Server) UpdateResources(protocols map[string]models.ProtocolProperties) error {
+	cfg, err := NewConfig(protocols["opcua"])
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.subscription == nil || s.client == nil {
+		return errNoSubscription
+	}
+
+	resources, err := s.subscribedResources(cfg.Resources)
+	if err != nil {
+		return fmt.Errorf("[%s] unable to list the subscribed resources: %v", s.deviceName, err)
+	}
+	wanted := make(map[string]bool, len(resources))
+	for _, resource := range resources {
+		wanted[resource] = true
+	}
+
+	var removed []string
+	var ids []uint32
+	for resource, item := range s.monitoredItems {
+		if !wanted[resource] {
+			removed = append(removed, resource)
+			ids = append(ids, item.id)
+		}
+	}
+	for _, batch := range batches(len(ids), s.itemsPerCall) {
+		ctx, cancel := s.client.requestContext()
+		res, err := s.subscription.Unmonitor(ctx, ids[batch[0]:batch[1]]...)
+		cancel()
+		if err != nil {
+			return fmt.Errorf("[%s] failed to remove monitored items: %v", s.deviceName, err)
+		}
+		for i, resource := range removed[batch[0]:batch[1]] {
+			if i < len(res.Results) && res.Results[i] != ua.StatusOK {
+				s.sdk.LoggingClient().Warnf("[%s] failed to remove the monitored item of %s: %s", s.deviceName, resource, statusCodeName(res.Results[i]))
+			}
+			delete(s.resourceMap, s.monitoredItems[resource].handle)
+			delete(s.monitoredItems, resource)
+			s.sdk.LoggingClient().Infof("[%s] stop incoming data listening for %s", s.deviceName, resource)
+		}
+	}
+
+	var added []string
+	for _, resource := range resources {
+		if _, ok := s.monitoredItems[resource]; !ok {
+			added = append(added, resource)
+		}
+	}
+	if len(added) > 0 {
+		if err := s.monitorResources(s.subscription, added); err != nil {
 			return err
 		}
+	}
+
+	s.config = cfg
+	return nil
+}
+
+// SetAdminState pauses the monitored items of the running subscription when the
+// device is locked, and resumes them when it is unlocked
+func (s *Server) SetAdminState(state models.AdminState) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 
-		s.sdk.LoggingClient().Infof("[%s] start incoming data listening for %s", s.deviceName, resource)
-		i++
+	if state == s.adminState {
+		return nil
+	}
+	if s.subscription == nil || s.client == nil {
+		return errNoSubscription
 	}
 
+	mode := ua.MonitoringModeReporting
+	if state == models.Locked {
+		mode = ua.MonitoringModeDisabled
+	}
+
+	if len(s.monitoredItems) > 0 {
+		ids := make([]uint32, 0, len(s.monitoredItems))
+		for _, item := range s.monitoredItems {
+			ids = append(ids, item.id)
+		}
+		for _, batch := range batches(len(ids), s.itemsPerCall) {
+			ctx, cancel := s.client.requestContext()
+			res, err := s.subscription.SetMonitoringMode(ctx, mode, ids[batch[0]:batch[1]]...)
+			cancel()
+			if err != nil {
+				return fmt.Errorf("[%s] failed to set monitoring mode %s: %v", s.deviceName, mode, err)
+			}
+			for i, status := range res.Results {
+				if status != ua.StatusOK {
+					s.sdk.LoggingClient().Warnf("[%s] failed to set monitoring mode %s on item %d: %v", s.deviceName, mode, ids[batch[0]+i], status)
+				}
+			}
+		}
+	}
+
+	s.adminState = state
+	s.sdk.LoggingClient().Infof("[%s] device %s, monitoring mode set to %s", s.deviceName, state, mode)
 	return nil
 }
 
@@ -102,47 +490,131 @@ func (s *Server) handleDataChange(dcn *ua.DataChangeNotification) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	policy := s.readingPolicy()
+	var values []*sdkModels.CommandValue
 	for _, item := range dcn.MonitoredItems {
-		var data any
-
-		variant := item.Value.Value
-		if variant != nil {
-			data = variant.Value()
-		} else {
+		if item.Value == nil {
 			continue
 		}
 		resourceName := s.resourceMap[item.ClientHandle]
-		if err := s.onIncomingDataReceived(data, resourceName); err != nil {
+		value, err := s.newDataChangeReading(resourceName, item.Value, policy)
+		if err != nil {
 			s.sdk.LoggingClient().Errorf("%v", err)
+			continue
+		}
+		if value != nil {
+			values = append(values, value)
 		}
 	}
+
+	s.publishReadings(values)
 }
 
-func (s *Server) onIncomingDataReceived(data interface{}, nodeResourceName string) error {
+// newDataChangeReading converts the data value notified for a device resource into a
+// reading, nil when dropped by the quality policy
+func (s *Server) newDataChangeReading(nodeResourceName string, dv *ua.DataValue, policy readingPolicy) (*sdkModels.CommandValue, error) {
 	deviceResource, ok := s.sdk.DeviceResource(s.deviceName, nodeResourceName)
 	if !ok {
-		return fmt.Errorf("[%s] Incoming reading ignored. No DeviceObject found: deviceResource=%v value=%v", s.deviceName, nodeResourceName, data)
+		return nil, fmt.Errorf("[%s] Incoming reading ignored. No DeviceObject found: deviceResource=%v", s.deviceName, nodeResourceName)
 	}
 
 	req := sdkModels.CommandRequest{
 		DeviceResourceName: nodeResourceName,
+		Attributes:         deviceResource.Attributes,
 		Type:               deviceResource.Properties.ValueType,
 	}
 
-	reading := data
-	result, err := result.NewResult(req, reading)
+	value, err := policy.newReading(req, dv)
 	if err != nil {
-		return fmt.Errorf("[%s] Incoming reading ignored. deviceResource=%v value=%v", s.deviceName, nodeResourceName, data)
+		return nil, fmt.Errorf("[%s] Incoming reading ignored. deviceResource=%v: %v", s.deviceName, nodeResourceName, err)
+	}
+	if value == nil {
+		s.sdk.LoggingClient().Debugf("[%s] Incoming reading dropped: deviceResource=%v status=%s", s.deviceName, nodeResourceName, statusCodeName(dv.Status))
+		return nil, nil
 	}
 
-	asyncValues := &sdkModels.AsyncValues{
+	s.sdk.LoggingClient().Infof("[%s] Incoming reading received: deviceResource=%v value=%v", s.deviceName, nodeResourceName, value.Value)
+	return value, nil
+}
+
+// publishReadings sends the readings of the data changes of one publish response,
+// grouped into events according to the ReadingBatching of the device. The caller holds mu.
+func (s *Server) publishReadings(values []*sdkModels.CommandValue) {
+	if len(values) == 0 {
+		return
+	}
+
+	batching := batchPerItem
+	if s.config != nil && s.config.ReadingBatching != "" {
+		batching = s.config.ReadingBatching
+	}
+	switch batching {
+	case batchPerPublish:
+		s.sendReadings(values)
+	case batchPerWindow:
+		s.pendingValues = append(s.pendingValues, values...)
+	default:
+		for _, value := range values {
+			s.sendReadings([]*sdkModels.CommandValue{value})
+		}
+	}
+}
+
+// flushReadings sends the readings collected during the current batch window
+func (s *Server) flushReadings() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.pendingValues) > 0 {
+		s.sendReadings(s.pendingValues)
+		s.pendingValues = nil
+	}
+}
+
+// sendReadings sends the readings as a single event. The event of a single reading is
+// named after its resource, the one of several readings after ReadingSourceName.
+func (s *Server) sendReadings(values []*sdkModels.CommandValue) {
+	var sourceName string
+	if len(values) > 1 {
+		sourceName = s.config.readingSourceName()
+	}
+	s.sdk.AsyncValuesChannel() <- &sdkModels.AsyncValues{
 		DeviceName:    s.deviceName,
-		CommandValues: []*sdkModels.CommandValue{result},
+		SourceName:    sourceName,
+		CommandValues: values,
 	}
+}
 
-	s.sdk.LoggingClient().Infof("[%s] Incoming reading received: deviceResource=%v value=%v", s.deviceName, nodeResourceName, data)
+func (s *Server) onIncomingDataReceived(data interface{}, nodeResourceName string) error {
+	value, err := s.newCommandValue(data, nodeResourceName)
+	if err != nil {
+		return err
+	}
 
-	s.sdk.AsyncValuesChannel() <- asyncValues
+	s.sendReadings([]*sdkModels.CommandValue{value})
 
 	return nil
 }
+
+// newCommandValue converts the value received for a device resource into a reading
+func (s *Server) newCommandValue(data interface{}, nodeResourceName string) (*sdkModels.CommandValue, error) {
+	deviceResource, ok := s.sdk.DeviceResource(s.deviceName, nodeResourceName)
+	if !ok {
+		return nil, fmt.Errorf("[%s] Incoming reading ignored. No DeviceObject found: deviceResource=%v value=%v", s.deviceName, nodeResourceName, data)
+	}
+
+	req := sdkModels.CommandRequest{
+		DeviceResourceName: nodeResourceName,
+		Type:               deviceResource.Properties.ValueType,
+	}
+
+	reading := data
+	result, err := result.NewResult(req, reading)
+	if err != nil {
+		return nil, fmt.Errorf("[%s] Incoming reading ignored. deviceResource=%v value=%v", s.deviceName, nodeResourceName, data)
+	}
+
+	s.sdk.LoggingClient().Infof("[%s] Incoming reading received: deviceResource=%v value=%v", s.deviceName, nodeResourceName, data)
+
+	return result, nil
+}
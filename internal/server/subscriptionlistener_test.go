@@ -7,11 +7,20 @@
 package server
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/edgexfoundry/device-opcua-go/internal/test"
+	"github.com/edgexfoundry/device-sdk-go/v3/pkg/interfaces/mocks"
+	sdkModels "github.com/edgexfoundry/device-sdk-go/v3/pkg/models"
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/clients/logger"
 	"github.com/edgexfoundry/go-mod-core-contracts/v3/models"
+	"github.com/gopcua/opcua"
 	"github.com/gopcua/opcua/ua"
 )
 
@@ -20,7 +29,7 @@ func Test_StartSubscriptionListener(t *testing.T) {
 		dsMock := test.NewDSMock(t)
 		dsMock.On("GetDeviceByName", "Test").Return(models.Device{}, fmt.Errorf("error"))
 
-		s := NewServer("Test", dsMock)
+		s := NewServer("Test", dsMock, nil)
 		err := s.StartSubscriptionListener()
 		if err == nil {
 			t.Error("expected err to exist in test environment")
@@ -35,7 +44,7 @@ func Test_configureMonitoredItems(t *testing.T) {
 		dsMock.On("DeviceResource", "Test", "b").Return(models.DeviceResource{}, false)
 		dsMock.On("DeviceResource", "Test", "c").Return(models.DeviceResource{}, false)
 
-		s := NewServer("Test", dsMock)
+		s := NewServer("Test", dsMock, nil)
 		s.config = &Config{Resources: []string{"a", "b", "c"}}
 		err := s.configureMonitoredItems(nil)
 		if err != nil {
@@ -44,12 +53,186 @@ func Test_configureMonitoredItems(t *testing.T) {
 	})
 }
 
+func TestServer_newPendingItem(t *testing.T) {
+	dsMock := test.NewDSMock(t)
+	dsMock.On("DeviceResource", "Test", "missing").Return(models.DeviceResource{}, false)
+	dsMock.On("DeviceResource", "Test", "invalid").Return(models.DeviceResource{Attributes: map[string]any{NODE: "ns=x;i=1"}}, true)
+	dsMock.On("DeviceResource", "Test", "timestamp").Return(models.DeviceResource{Name: "timestamp", Attributes: map[string]any{NODE: "ns=2;s=A", TIMESTAMPSOURCE: "foobar"}}, true)
+	dsMock.On("DeviceResource", "Test", "valid").Return(models.DeviceResource{Attributes: map[string]any{NODE: "ns=2;s=A"}}, true)
+
+	s := NewServer("Test", dsMock, nil)
+	if _, err := s.newPendingItem("missing"); err == nil {
+		t.Error("expected missing resource error")
+	}
+	if _, err := s.newPendingItem("invalid"); err == nil {
+		t.Error("expected invalid node id error")
+	}
+	if _, err := s.newPendingItem("timestamp"); err == nil || !strings.Contains(err.Error(), "device resource timestamp") {
+		t.Errorf("expected invalid attribute error of the resource, got = %v", err)
+	}
+
+	first, err := s.newPendingItem("valid")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	s.adminState = models.Locked
+	second, err := s.newPendingItem("valid")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if first.handle == second.handle {
+		t.Errorf("expected distinct client handles, got %d twice", first.handle)
+	}
+	if first.request.MonitoringMode != ua.MonitoringModeReporting || second.request.MonitoringMode != ua.MonitoringModeDisabled {
+		t.Errorf("expected reporting then disabled items, got %v and %v", first.request.MonitoringMode, second.request.MonitoringMode)
+	}
+}
+
+func Test_itemsPerCall(t *testing.T) {
+	for limit, want := range map[uint32]int{0: defaultItemsPerCall, 100: 100, 5000: defaultItemsPerCall} {
+		if got := itemsPerCall(limit); got != want {
+			t.Errorf("itemsPerCall(%d) = %d, want %d", limit, got, want)
+		}
+	}
+}
+
+func Test_batches(t *testing.T) {
+	tests := []struct {
+		name string
+		n    int
+		size int
+		want [][2]int
+	}{
+		{name: "no items", n: 0, size: 10},
+		{name: "single batch", n: 3, size: 10, want: [][2]int{{0, 3}}},
+		{name: "last batch shorter", n: 25, size: 10, want: [][2]int{{0, 10}, {10, 20}, {20, 25}}},
+		{name: "unset size", n: 3, want: [][2]int{{0, 3}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := batches(tt.n, tt.size); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("batches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestServer_UpdateResources(t *testing.T) {
+	t.Run("no subscription", func(t *testing.T) {
+		s := NewServer("Test", test.NewDSMock(t), nil)
+		protocols := map[string]models.ProtocolProperties{"opcua": {"Endpoint": "opc.tcp://test", "Resources": []string{"a"}}}
+		if err := s.UpdateResources(protocols); !errors.Is(err, errNoSubscription) {
+			t.Errorf("Server.UpdateResources() error = %v, want %v", err, errNoSubscription)
+		}
+	})
+
+	t.Run("invalid protocol properties", func(t *testing.T) {
+		s := NewServer("Test", test.NewDSMock(t), nil)
+		protocols := map[string]models.ProtocolProperties{"opcua": {"Resources": make(chan int)}}
+		if err := s.UpdateResources(protocols); err == nil {
+			t.Error("expected an error")
+		}
+	})
+}
+
+func TestServer_SetAdminState(t *testing.T) {
+	tests := []struct {
+		name    string
+		current models.AdminState
+		state   models.AdminState
+		wantErr error
+	}{
+		{
+			name:    "OK - unchanged admin state",
+			current: models.Unlocked,
+			state:   models.Unlocked,
+		},
+		{
+			name:    "NOK - no subscription to pause",
+			current: models.Unlocked,
+			state:   models.Locked,
+			wantErr: errNoSubscription,
+		},
+		{
+			name:    "NOK - no subscription to resume",
+			current: models.Locked,
+			state:   models.Unlocked,
+			wantErr: errNoSubscription,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewServer("Test", test.NewDSMock(t), nil)
+			s.adminState = tt.current
+			if err := s.SetAdminState(tt.state); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Server.SetAdminState() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestServer_handleNotification(t *testing.T) {
+	tests := []struct {
+		name  string
+		res   *opcua.PublishNotificationData
+		state opcua.ConnState
+		want  error
+	}{
+		{
+			name:  "data change",
+			res:   &opcua.PublishNotificationData{Value: &ua.DataChangeNotification{}},
+			state: opcua.Connected,
+		},
+		{
+			name:  "status change",
+			res:   &opcua.PublishNotificationData{Value: &ua.StatusChangeNotification{Status: ua.StatusBadTimeout}},
+			state: opcua.Connected,
+			want:  errSubscriptionLost,
+		},
+		{
+			name:  "subscription error",
+			res:   &opcua.PublishNotificationData{Error: ua.StatusBadSubscriptionIDInvalid},
+			state: opcua.Connected,
+			want:  errSubscriptionLost,
+		},
+		{
+			name:  "session error",
+			res:   &opcua.PublishNotificationData{Error: ua.StatusBadSessionClosed},
+			state: opcua.Connected,
+			want:  errConnectionLost,
+		},
+		{
+			name:  "error after disconnection",
+			res:   &opcua.PublishNotificationData{Error: errors.New("EOF")},
+			state: opcua.Disconnected,
+			want:  errConnectionLost,
+		},
+		{
+			name:  "other error",
+			res:   &opcua.PublishNotificationData{Error: errors.New("unknown NotificationData parameter")},
+			state: opcua.Connected,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewServer("Test", test.NewDSMock(t), nil)
+			err := s.handleNotification(tt.res, tt.state)
+			if tt.want == nil && err != nil {
+				t.Errorf("Server.handleNotification() error = %v, want none", err)
+			}
+			if tt.want != nil && !errors.Is(err, tt.want) {
+				t.Errorf("Server.handleNotification() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
 func Test_onIncomingDataReceived(t *testing.T) {
 	t.Run("device resource unknown", func(t *testing.T) {
 		dsMock := test.NewDSMock(t)
 		dsMock.On("DeviceResource", "Test", "TestResource").Return(models.DeviceResource{}, false)
 
-		s := NewServer("Test", dsMock)
+		s := NewServer("Test", dsMock, nil)
 		err := s.onIncomingDataReceived("42", "TestResource")
 		if err == nil {
 			t.Error("expected err to exist in test environment")
@@ -57,6 +240,55 @@ func Test_onIncomingDataReceived(t *testing.T) {
 	})
 }
 
+func TestServer_publishReadings(t *testing.T) {
+	tests := []struct {
+		name       string
+		batching   string
+		sourceName string
+		want       []int
+		wantSource []string
+	}{
+		{name: "one event per value by default", want: []int{1, 1, 1}, wantSource: []string{"", "", ""}},
+		{name: "one event per value", batching: batchPerItem, want: []int{1, 1, 1}, wantSource: []string{"", "", ""}},
+		{name: "one event per publish response", batching: batchPerPublish, want: []int{3}, wantSource: []string{defaultReadingSourceName}},
+		{name: "one event per window", batching: batchPerWindow, sourceName: "Batch", want: []int{6}, wantSource: []string{"Batch"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ch := make(chan *sdkModels.AsyncValues, 10)
+			dsMock := mocks.NewDeviceServiceSDK(t)
+			dsMock.On("LoggingClient").Return(logger.NewMockClient()).Maybe()
+			dsMock.On("AsyncValuesChannel").Return(ch)
+
+			s := NewServer("Test", dsMock, nil)
+			s.config = &Config{ReadingBatching: tt.batching, ReadingBatchWindow: Duration(time.Second), ReadingSourceName: tt.sourceName}
+			values := []*sdkModels.CommandValue{{DeviceResourceName: "A"}, {DeviceResourceName: "B"}, {DeviceResourceName: "C"}}
+			s.mu.Lock()
+			s.publishReadings(values)
+			if tt.batching == batchPerWindow {
+				s.publishReadings(values)
+			}
+			s.mu.Unlock()
+			s.flushReadings()
+			close(ch)
+
+			var got []int
+			var sources []string
+			for event := range ch {
+				got = append(got, len(event.CommandValues))
+				sources = append(sources, event.SourceName)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("readings per event = %v, want %v", got, tt.want)
+			}
+			// the SDK names the event of a single reading after its resource
+			if !reflect.DeepEqual(sources, tt.wantSource) {
+				t.Errorf("source names = %v, want %v", sources, tt.wantSource)
+			}
+		})
+	}
+}
+
 func TestDriver_initClient(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -85,7 +317,7 @@ func TestDriver_initClient(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			dsMock := test.NewDSMock(t)
-			s := NewServer("Test", dsMock)
+			s := NewServer("Test", dsMock, nil)
 
 			if !tt.wantErr {
 				server := test.NewServer("../test/opcua_server.py")
@@ -93,7 +325,7 @@ func TestDriver_initClient(t *testing.T) {
 			}
 
 			s.config = tt.config
-			err := s.initClient()
+			_, err := s.initClient(context.Background())
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Driver.getClient() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -105,14 +337,14 @@ func TestDriver_initClient(t *testing.T) {
 func TestDriver_handleDataChange(t *testing.T) {
 	t.Run("OK - no monitored items", func(t *testing.T) {
 		dsMock := test.NewDSMock(t)
-		s := NewServer("Test", dsMock)
+		s := NewServer("Test", dsMock, nil)
 		s.handleDataChange(&ua.DataChangeNotification{MonitoredItems: make([]*ua.MonitoredItemNotification, 0)})
 	})
 
 	t.Run("OK - call onIncomingDataReceived", func(t *testing.T) {
 		dsMock := test.NewDSMock(t)
 		dsMock.On("DeviceResource", "Test", "").Return(models.DeviceResource{Name: "TestResource"}, true)
-		s := NewServer("Test", dsMock)
+		s := NewServer("Test", dsMock, nil)
 
 		s.handleDataChange(&ua.DataChangeNotification{
 			MonitoredItems: []*ua.MonitoredItemNotification{
@@ -0,0 +1,66 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2022 Schneider Electric
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gopcua/opcua/ua"
+	"github.com/spf13/cast"
+)
+
+// Timestamp policies, selecting the time used as the Origin of a reading
+const (
+	timestampSource = "Source"
+	timestampServer = "Server"
+	timestampLocal  = "Local"
+)
+
+// timestampPolicy returns the policy of a device resource, falling back to the device one
+func timestampPolicy(attrs map[string]any, device string) string {
+	if policy, ok := attrs[TIMESTAMPSOURCE]; ok {
+		if p := cast.ToString(policy); isTimestampPolicy(p) {
+			return p
+		}
+	}
+	if isTimestampPolicy(device) {
+		return device
+	}
+	return timestampLocal
+}
+
+func isTimestampPolicy(policy string) bool {
+	return policy == timestampSource || policy == timestampServer || policy == timestampLocal
+}
+
+// validateTimestampPolicy checks the timestampSource attribute of a device resource
+func validateTimestampPolicy(attrs map[string]any) error {
+	v, ok := attrs[TIMESTAMPSOURCE]
+	if !ok {
+		return nil
+	}
+	if !isTimestampPolicy(cast.ToString(v)) {
+		return fmt.Errorf("invalid %s %v: must be %s, %s or %s", TIMESTAMPSOURCE, v, timestampSource, timestampServer, timestampLocal)
+	}
+	return nil
+}
+
+// readingOrigin returns the Origin of a reading in nanoseconds. A missing source
+// timestamp falls back to the server timestamp, and a missing server timestamp
+// to the local time.
+func readingOrigin(policy string, dv *ua.DataValue) int64 {
+	if dv != nil {
+		if policy == timestampSource && !dv.SourceTimestamp.IsZero() {
+			return dv.SourceTimestamp.UnixNano()
+		}
+		if policy != timestampLocal && !dv.ServerTimestamp.IsZero() {
+			return dv.ServerTimestamp.UnixNano()
+		}
+	}
+	return time.Now().UnixNano()
+}
@@ -0,0 +1,78 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2022 Schneider Electric
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gopcua/opcua/ua"
+)
+
+func Test_timestampPolicy(t *testing.T) {
+	tests := []struct {
+		name   string
+		attrs  map[string]any
+		device string
+		want   string
+	}{
+		{name: "default", want: timestampLocal},
+		{name: "device policy", device: timestampSource, want: timestampSource},
+		{name: "resource overrides device", attrs: map[string]any{TIMESTAMPSOURCE: "Server"}, device: timestampSource, want: timestampServer},
+		{name: "invalid resource policy", attrs: map[string]any{TIMESTAMPSOURCE: "Device"}, device: timestampSource, want: timestampSource},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := timestampPolicy(tt.attrs, tt.device); got != tt.want {
+				t.Errorf("timestampPolicy() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_validateTimestampPolicy(t *testing.T) {
+	if err := validateTimestampPolicy(map[string]any{TIMESTAMPSOURCE: "Source"}); err != nil {
+		t.Errorf("expected no error, got = %v", err)
+	}
+	if err := validateTimestampPolicy(map[string]any{TIMESTAMPSOURCE: "Device"}); err == nil {
+		t.Error("expected invalid policy error")
+	}
+}
+
+func Test_readingOrigin(t *testing.T) {
+	source := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	server := source.Add(time.Second)
+
+	tests := []struct {
+		name   string
+		policy string
+		dv     *ua.DataValue
+		want   time.Time
+	}{
+		{name: "source", policy: timestampSource, dv: &ua.DataValue{SourceTimestamp: source, ServerTimestamp: server}, want: source},
+		{name: "source missing", policy: timestampSource, dv: &ua.DataValue{ServerTimestamp: server}, want: server},
+		{name: "server", policy: timestampServer, dv: &ua.DataValue{SourceTimestamp: source, ServerTimestamp: server}, want: server},
+		{name: "server missing", policy: timestampServer, dv: &ua.DataValue{SourceTimestamp: source}},
+		{name: "local", policy: timestampLocal, dv: &ua.DataValue{SourceTimestamp: source, ServerTimestamp: server}},
+		{name: "no data value", policy: timestampSource},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := time.Now().UnixNano()
+			got := readingOrigin(tt.policy, tt.dv)
+			if !tt.want.IsZero() {
+				if got != tt.want.UnixNano() {
+					t.Errorf("readingOrigin() = %v, want %v", got, tt.want.UnixNano())
+				}
+				return
+			}
+			if got < before || got > time.Now().UnixNano() {
+				t.Errorf("readingOrigin() = %v, want the local time", got)
+			}
+		})
+	}
+}
@@ -8,6 +8,7 @@ package server
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/gopcua/opcua/ua"
 )
@@ -17,6 +18,23 @@ const (
 	OBJECT   string = "objectId"
 	METHOD   string = "methodId"
 	INPUTMAP string = "inputMap"
+
+	INDEXRANGE string = "indexRange"
+
+	TIMESTAMPSOURCE string = "timestampSource"
+
+	SUBSCRIBE        string = "subscribe"
+	SAMPLINGINTERVAL string = "samplingInterval"
+	QUEUESIZE        string = "queueSize"
+	DISCARDOLDEST    string = "discardOldest"
+
+	DEADBANDTYPE      string = "deadbandType"
+	DEADBANDVALUE     string = "deadbandValue"
+	DATACHANGETRIGGER string = "dataChangeTrigger"
+
+	EVENTFIELDS string = "eventFields"
+	EVENTTYPES  string = "eventTypes"
+	MINSEVERITY string = "minSeverity"
 )
 
 func getNodeID(attrs map[string]interface{}, id string) (*ua.NodeID, error) {
@@ -27,3 +45,12 @@ func getNodeID(attrs map[string]interface{}, id string) (*ua.NodeID, error) {
 
 	return ua.ParseNodeID(identifier.(string))
 }
+
+// statusCodeName returns the symbolic name of a status code, such as BadNodeIDUnknown
+func statusCodeName(code ua.StatusCode) string {
+	// the low 16 bits hold flags which are not part of the code
+	if d, ok := ua.StatusCodes[code&0xFFFF0000]; ok {
+		return strings.TrimPrefix(d.Name, "Status")
+	}
+	return fmt.Sprintf("0x%08X", uint32(code))
+}
@@ -13,7 +13,6 @@ import (
 
 	"github.com/edgexfoundry/device-opcua-go/pkg/command"
 	sdkModel "github.com/edgexfoundry/device-sdk-go/v3/pkg/models"
-	"github.com/gopcua/opcua"
 	"github.com/gopcua/opcua/ua"
 )
 
@@ -32,45 +31,70 @@ func (s *Server) ProcessWriteCommands(reqs []sdkModel.CommandRequest, params []*
 func (s *Server) handleWriteCommandRequest(req sdkModel.CommandRequest,
 	param *sdkModel.CommandValue) error {
 
-	id, err := getNodeID(req.Attributes, NODE)
+	writeValue, err := newWriteValue(req, param)
 	if err != nil {
-		return fmt.Errorf("Driver.handleWriteCommands: invalid node id: %v", err)
+		return err
 	}
 
-	value, err := command.NewValue(req.Type, param)
+	request := &ua.WriteRequest{
+		NodesToWrite: []*ua.WriteValue{writeValue},
+	}
+
+	client, err := s.connectedClient()
+	if err != nil {
+		return fmt.Errorf("Driver.handleWriteCommands: client not initialized: %s", err)
+	}
+
+	ctx, cancel := client.requestContext()
+	defer cancel()
+	resp, err := client.Write(ctx, request)
 	if err != nil {
+		s.sdk.LoggingClient().Errorf("Driver.handleWriteCommands: Write value %v failed: %s", writeValue.Value.Value, err)
 		return err
 	}
+	s.sdk.LoggingClient().Infof("Driver.handleWriteCommands: write sucessfully, %v", resp.Results[0])
+	return nil
+}
 
-	v, err := ua.NewVariant(value)
+// newWriteValue builds the write of a command parameter to the node, or to the
+// range of the node, of a device resource
+func newWriteValue(req sdkModel.CommandRequest, param *sdkModel.CommandValue) (*ua.WriteValue, error) {
+	id, err := getNodeID(req.Attributes, NODE)
 	if err != nil {
-		return fmt.Errorf("Driver.handleWriteCommands: invalid value: %v", err)
+		return nil, fmt.Errorf("Driver.handleWriteCommands: invalid node id: %v", err)
 	}
 
-	request := &ua.WriteRequest{
-		NodesToWrite: []*ua.WriteValue{
-			{
-				NodeID:      id,
-				AttributeID: ua.AttributeIDValue,
-				Value: &ua.DataValue{
-					EncodingMask: ua.DataValueValue, // encoding mask
-					Value:        v,
-				},
-			},
-		},
+	value, err := command.NewValue(req.Type, param)
+	if err != nil {
+		return nil, err
 	}
 
-	if s.client == nil || s.client.State() == opcua.Closed || s.client.State() == opcua.Disconnected {
-		if err := s.Connect(); err != nil {
-			return fmt.Errorf("Driver.handleWriteCommands: client not initialized: %s", err)
+	r, dims, err := indexRange(req.Attributes)
+	if err != nil {
+		return nil, fmt.Errorf("Driver.handleWriteCommands: %v", err)
+	}
+	if r != "" {
+		if value, err = shapeValue(value, dims); err != nil {
+			return nil, fmt.Errorf("Driver.handleWriteCommands: invalid value: %v", err)
 		}
 	}
+	// a []byte is encoded as a ByteString unless typed as an array of Byte
+	if b, ok := value.([]uint8); ok {
+		value = ua.ByteArray(b)
+	}
 
-	resp, err := s.client.Write(s.client.ctx, request)
+	v, err := ua.NewVariant(value)
 	if err != nil {
-		s.sdk.LoggingClient().Errorf("Driver.handleWriteCommands: Write value %v failed: %s", v, err)
-		return err
+		return nil, fmt.Errorf("Driver.handleWriteCommands: invalid value: %v", err)
 	}
-	s.sdk.LoggingClient().Infof("Driver.handleWriteCommands: write sucessfully, %v", resp.Results[0])
-	return nil
+
+	return &ua.WriteValue{
+		NodeID:      id,
+		AttributeID: ua.AttributeIDValue,
+		IndexRange:  r,
+		Value: &ua.DataValue{
+			EncodingMask: ua.DataValueValue, // encoding mask
+			Value:        v,
+		},
+	}, nil
 }
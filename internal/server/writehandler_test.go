@@ -9,6 +9,7 @@ package server
 import (
 	"context"
 	"fmt"
+	"reflect"
 	"testing"
 
 	"github.com/edgexfoundry/device-opcua-go/internal/test"
@@ -141,12 +142,12 @@ func TestDriver_ProcessWriteCommands(t *testing.T) {
 			}
 
 			dsMock := test.NewDSMock(t)
-			s := NewServer(tt.args.deviceName, dsMock)
+			s := NewServer(tt.args.deviceName, dsMock, nil)
 			if tt.nilClient {
 				s.client = nil
 				dsMock.On("GetDeviceByName", tt.args.deviceName).Return(models.Device{}, fmt.Errorf("error"))
 			} else {
-				s.client = &Client{client, context.Background()}
+				s.client = &Client{Client: client, ctx: context.Background(), timeout: defaultRequestTimeout}
 			}
 			if err := s.ProcessWriteCommands(tt.args.reqs, tt.args.params); (err != nil) != tt.wantErr {
 				t.Errorf("Driver.HandleWriteCommands() error = %v, wantErr %v", err, tt.wantErr)
@@ -154,3 +155,74 @@ func TestDriver_ProcessWriteCommands(t *testing.T) {
 		})
 	}
 }
+
+func Test_newWriteValue(t *testing.T) {
+	tests := []struct {
+		name      string
+		attrs     map[string]interface{}
+		param     *sdkModel.CommandValue
+		wantRange string
+		wantType  ua.TypeID
+		wantArray bool
+		wantDims  []int32
+		wantErr   bool
+	}{
+		{
+			name:     "OK - scalar",
+			attrs:    map[string]interface{}{NODE: "ns=2;s=rw_int32"},
+			param:    &sdkModel.CommandValue{Type: common.ValueTypeInt32, Value: int32(42)},
+			wantType: ua.TypeIDInt32,
+		},
+		{
+			name:      "OK - uint8 index",
+			attrs:     map[string]interface{}{NODE: "ns=2;s=rw_bytes", INDEXRANGE: "2"},
+			param:     &sdkModel.CommandValue{Type: common.ValueTypeUint8, Value: uint8(7)},
+			wantRange: "2",
+			wantType:  ua.TypeIDByte,
+			wantArray: true,
+		},
+		{
+			name:      "OK - uint8 array",
+			attrs:     map[string]interface{}{NODE: "ns=2;s=rw_bytes"},
+			param:     &sdkModel.CommandValue{Type: common.ValueTypeUint8Array, Value: []uint8{1, 2}},
+			wantType:  ua.TypeIDByte,
+			wantArray: true,
+		},
+		{
+			name:      "OK - two-dimensional range",
+			attrs:     map[string]interface{}{NODE: "ns=2;s=rw_matrix", INDEXRANGE: "0:1,2:3"},
+			param:     &sdkModel.CommandValue{Type: common.ValueTypeInt32Array, Value: []int32{1, 2, 3, 4}},
+			wantRange: "0:1,2:3",
+			wantType:  ua.TypeIDInt32,
+			wantArray: true,
+			wantDims:  []int32{2, 2},
+		},
+		{
+			name:    "NOK - element count mismatch",
+			attrs:   map[string]interface{}{NODE: "ns=2;s=rw_int32", INDEXRANGE: "0:2"},
+			param:   &sdkModel.CommandValue{Type: common.ValueTypeInt32Array, Value: []int32{1, 2}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := sdkModel.CommandRequest{DeviceResourceName: "TestResource1", Attributes: tt.attrs, Type: tt.param.Type}
+			got, err := newWriteValue(req, tt.param)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("newWriteValue() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got.IndexRange != tt.wantRange {
+				t.Errorf("newWriteValue() IndexRange = %q, want %q", got.IndexRange, tt.wantRange)
+			}
+			// a ByteString would not match the Byte elements of the node
+			v := got.Value.Value
+			if v.Type() != tt.wantType || v.Has(ua.VariantArrayValues) != tt.wantArray || !reflect.DeepEqual(v.ArrayDimensions(), tt.wantDims) {
+				t.Errorf("newWriteValue() value type %v array %v dimensions %v, want %v %v %v",
+					v.Type(), v.Has(ua.VariantArrayValues), v.ArrayDimensions(), tt.wantType, tt.wantArray, tt.wantDims)
+			}
+		})
+	}
+}
@@ -111,6 +111,42 @@ func Test_newCommandValue(t *testing.T) {
 			want:    float64(5),
 			wantErr: false,
 		},
+		{
+			name:    "OK - bool array value",
+			args:    args{valueType: common.ValueTypeBoolArray, param: &sdkModel.CommandValue{Value: []bool{true, false}, Type: common.ValueTypeBoolArray}},
+			want:    []bool{true, false},
+			wantErr: false,
+		},
+		{
+			name:    "OK - uint8 array value",
+			args:    args{valueType: common.ValueTypeUint8Array, param: &sdkModel.CommandValue{Value: []uint8{1, 2}, Type: common.ValueTypeUint8Array}},
+			want:    []uint8{1, 2},
+			wantErr: false,
+		},
+		{
+			name:    "OK - int32 array value",
+			args:    args{valueType: common.ValueTypeInt32Array, param: &sdkModel.CommandValue{Value: []int32{1, -2}, Type: common.ValueTypeInt32Array}},
+			want:    []int32{1, -2},
+			wantErr: false,
+		},
+		{
+			name:    "OK - float64 array value",
+			args:    args{valueType: common.ValueTypeFloat64Array, param: &sdkModel.CommandValue{Value: []float64{1.5, 2}, Type: common.ValueTypeFloat64Array}},
+			want:    []float64{1.5, 2},
+			wantErr: false,
+		},
+		{
+			name:    "OK - string array value",
+			args:    args{valueType: common.ValueTypeStringArray, param: &sdkModel.CommandValue{Value: []string{"a", "b"}, Type: common.ValueTypeStringArray}},
+			want:    []string{"a", "b"},
+			wantErr: false,
+		},
+		{
+			name:    "NOK - float32 array value - mismatching types",
+			args:    args{valueType: common.ValueTypeFloat32Array, param: &sdkModel.CommandValue{Value: []float64{1.5}, Type: common.ValueTypeFloat64Array}},
+			want:    []float32(nil),
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -10,6 +10,7 @@ package result
 
 import (
 	"fmt"
+	"reflect"
 	"time"
 
 	sdkModel "github.com/edgexfoundry/device-sdk-go/v3/pkg/models"
@@ -90,6 +91,16 @@ func NewResult(req sdkModel.CommandRequest, reading interface{}) (*sdkModel.Comm
 		if err != nil {
 			return nil, fmt.Errorf(castError, req.DeviceResourceName, err)
 		}
+	case common.ValueTypeObject:
+		val = reading
+	case common.ValueTypeBoolArray, common.ValueTypeStringArray,
+		common.ValueTypeUint8Array, common.ValueTypeUint16Array, common.ValueTypeUint32Array, common.ValueTypeUint64Array,
+		common.ValueTypeInt8Array, common.ValueTypeInt16Array, common.ValueTypeInt32Array, common.ValueTypeInt64Array,
+		common.ValueTypeFloat32Array, common.ValueTypeFloat64Array:
+		val, err = newArrayValue(req.Type, reading)
+		if err != nil {
+			return nil, fmt.Errorf(castError, req.DeviceResourceName, err)
+		}
 	default:
 		err = fmt.Errorf("return result fail, none supported value type: %v", req.Type)
 		return nil, err
@@ -103,3 +114,70 @@ func NewResult(req sdkModel.CommandRequest, reading interface{}) (*sdkModel.Comm
 
 	return result, err
 }
+
+// newArrayValue converts each element of an array reading to the element type of the value type
+func newArrayValue(valueType string, reading interface{}) (interface{}, error) {
+	switch valueType {
+	case common.ValueTypeBoolArray:
+		return castArray(reading, cast.ToBoolE)
+	case common.ValueTypeStringArray:
+		return castArray(reading, cast.ToStringE)
+	case common.ValueTypeUint8Array:
+		return castArray(reading, cast.ToUint8E)
+	case common.ValueTypeUint16Array:
+		return castArray(reading, cast.ToUint16E)
+	case common.ValueTypeUint32Array:
+		return castArray(reading, cast.ToUint32E)
+	case common.ValueTypeUint64Array:
+		return castArray(reading, cast.ToUint64E)
+	case common.ValueTypeInt8Array:
+		return castArray(reading, cast.ToInt8E)
+	case common.ValueTypeInt16Array:
+		return castArray(reading, cast.ToInt16E)
+	case common.ValueTypeInt32Array:
+		return castArray(reading, cast.ToInt32E)
+	case common.ValueTypeInt64Array:
+		return castArray(reading, cast.ToInt64E)
+	case common.ValueTypeFloat32Array:
+		return castArray(reading, cast.ToFloat32E)
+	case common.ValueTypeFloat64Array:
+		return castArray(reading, cast.ToFloat64E)
+	default:
+		return nil, fmt.Errorf("none supported array value type: %v", valueType)
+	}
+}
+
+func castArray[T any](reading interface{}, castE func(interface{}) (T, error)) ([]T, error) {
+	elements, ok := arrayElements(reading)
+	if !ok {
+		return nil, fmt.Errorf("%v of type %T is not an array", reading, reading)
+	}
+	values := make([]T, len(elements))
+	for i, element := range elements {
+		v, err := castE(element)
+		if err != nil {
+			return nil, fmt.Errorf("element %d: %v", i, err)
+		}
+		values[i] = v
+	}
+	return values, nil
+}
+
+// arrayElements returns the elements of a slice or array reading, the nested
+// arrays of a multidimensional reading being flattened in row-major order
+func arrayElements(reading interface{}) ([]interface{}, bool) {
+	v := reflect.ValueOf(reading)
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return nil, false
+	}
+	elements := make([]interface{}, 0, v.Len())
+	for i := 0; i < v.Len(); i++ {
+		element := v.Index(i).Interface()
+		if nested, ok := arrayElements(element); ok {
+			elements = append(elements, nested...)
+			continue
+		}
+		elements = append(elements, element)
+	}
+	return elements, true
+}
@@ -9,6 +9,7 @@ package result
 
 import (
 	"fmt"
+	"math"
 	"strings"
 	"testing"
 
@@ -586,3 +587,66 @@ func TestNewResult_numberToString(t *testing.T) {
 		t.Errorf("Convert new result(%v) failed, error: %v", val, err)
 	}
 }
+
+func TestNewResult_object(t *testing.T) {
+	var reading interface{} = map[string]any{"Message": "High level", "Severity": uint16(700)}
+	req := models.CommandRequest{
+		DeviceResourceName: "alarms",
+		Type:               common.ValueTypeObject,
+	}
+
+	cmdVal, err := NewResult(req, reading)
+	if err != nil {
+		t.Fatalf("Fail to create new ReadCommand result, %v", err)
+	}
+	val, err := cmdVal.ObjectValue()
+	if val.(map[string]any)["Message"] != "High level" || err != nil {
+		t.Errorf("Convert new result(%v) failed, error: %v", val, err)
+	}
+}
+
+func TestNewResult_arrays(t *testing.T) {
+	tests := []struct {
+		name      string
+		valueType string
+		reading   interface{}
+		want      interface{}
+		wantErr   bool
+	}{
+		{name: "bool array", valueType: common.ValueTypeBoolArray, reading: []bool{true, false}, want: []bool{true, false}},
+		{name: "string array", valueType: common.ValueTypeStringArray, reading: []string{"a", "b"}, want: []string{"a", "b"}},
+		{name: "uint8 array from a byte string", valueType: common.ValueTypeUint8Array, reading: []byte{1, 2}, want: []uint8{1, 2}},
+		{name: "uint16 array", valueType: common.ValueTypeUint16Array, reading: []uint16{1, 2}, want: []uint16{1, 2}},
+		{name: "uint32 array", valueType: common.ValueTypeUint32Array, reading: []uint32{1, 2}, want: []uint32{1, 2}},
+		{name: "uint64 array", valueType: common.ValueTypeUint64Array, reading: []uint64{1, 2}, want: []uint64{1, 2}},
+		{name: "int8 array", valueType: common.ValueTypeInt8Array, reading: []int8{-1, 2}, want: []int8{-1, 2}},
+		{name: "int16 array", valueType: common.ValueTypeInt16Array, reading: []int16{-1, 2}, want: []int16{-1, 2}},
+		{name: "int32 array", valueType: common.ValueTypeInt32Array, reading: []int32{-1, 2}, want: []int32{-1, 2}},
+		{name: "int64 array", valueType: common.ValueTypeInt64Array, reading: []int64{-1, 2}, want: []int64{-1, 2}},
+		{name: "float32 array from doubles", valueType: common.ValueTypeFloat32Array, reading: []float64{1.5, 2}, want: []float32{1.5, 2}},
+		{name: "float64 array", valueType: common.ValueTypeFloat64Array, reading: []float64{1.5, 2}, want: []float64{1.5, 2}},
+		{name: "empty array", valueType: common.ValueTypeInt32Array, reading: []int32{}, want: []int32{}},
+		{name: "multidimensional array", valueType: common.ValueTypeInt32Array, reading: [][]int32{{1, 2}, {3, 4}}, want: []int32{1, 2, 3, 4}},
+		{name: "element out of range", valueType: common.ValueTypeInt8Array, reading: []int32{1, 200}, wantErr: true},
+		{name: "float32 element out of range", valueType: common.ValueTypeFloat32Array, reading: []float64{1, math.MaxFloat64}, wantErr: true},
+		{name: "not an array", valueType: common.ValueTypeInt32Array, reading: int32(1), wantErr: true},
+		{name: "element not convertible", valueType: common.ValueTypeBoolArray, reading: []string{"true", "maybe"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := models.CommandRequest{
+				DeviceResourceName: "spectrum",
+				Type:               tt.valueType,
+			}
+
+			cmdVal, err := NewResult(req, tt.reading)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, cmdVal.Value)
+			assert.Equal(t, tt.valueType, cmdVal.Type)
+		})
+	}
+}
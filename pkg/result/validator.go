@@ -15,11 +15,41 @@ import (
 	"github.com/spf13/cast"
 )
 
-// checkValueInRange checks value range is valid
+// arrayElementTypes maps the array value types to the value type of their elements
+var arrayElementTypes = map[string]string{
+	common.ValueTypeBoolArray:    common.ValueTypeBool,
+	common.ValueTypeStringArray:  common.ValueTypeString,
+	common.ValueTypeUint8Array:   common.ValueTypeUint8,
+	common.ValueTypeUint16Array:  common.ValueTypeUint16,
+	common.ValueTypeUint32Array:  common.ValueTypeUint32,
+	common.ValueTypeUint64Array:  common.ValueTypeUint64,
+	common.ValueTypeInt8Array:    common.ValueTypeInt8,
+	common.ValueTypeInt16Array:   common.ValueTypeInt16,
+	common.ValueTypeInt32Array:   common.ValueTypeInt32,
+	common.ValueTypeInt64Array:   common.ValueTypeInt64,
+	common.ValueTypeFloat32Array: common.ValueTypeFloat32,
+	common.ValueTypeFloat64Array: common.ValueTypeFloat64,
+}
+
+// checkValueInRange checks value range is valid, for every element of an array
 func checkValueInRange(valueType string, reading interface{}) bool {
 	isValid := false
 
-	if valueType == common.ValueTypeString || valueType == common.ValueTypeBool {
+	if elementType, ok := arrayElementTypes[valueType]; ok {
+		elements, ok := arrayElements(reading)
+		if !ok {
+			// the conversion reports a reading that is not an array
+			return true
+		}
+		for _, element := range elements {
+			if !checkValueInRange(elementType, element) {
+				return false
+			}
+		}
+		return true
+	}
+
+	if valueType == common.ValueTypeString || valueType == common.ValueTypeBool || valueType == common.ValueTypeObject {
 		return true
 	}
 